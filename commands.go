@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/achievement"
+	"github.com/nyan-statusline-cc/internal/paths"
+	"github.com/nyan-statusline-cc/internal/render"
+	"github.com/nyan-statusline-cc/internal/stats"
+)
+
+// runStats stats 子命令: 输出完整统计报告
+// Parameters:
+//   - args: 子命令参数 (--json, --since YYYY-MM-DD, --until YYYY-MM-DD)
+//   - p: 数据目录
+//   - w: 输出目标
+//
+// Return:
+//   - error: 参数或读取错误
+func runStats(args []string, p paths.Paths, w io.Writer) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "以 JSON 输出")
+	sinceStr := fs.String("since", "", "开始日期 (YYYY-MM-DD), 默认为一年前")
+	untilStr := fs.String("until", "", "结束日期 (YYYY-MM-DD), 默认为今天")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	cfg := render.LoadConfig(&p)
+	now := time.Now().In(cfg.Location())
+	since, err := parseDate(*sinceStr, now.Location())
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	until, err := parseDate(*untilStr, now.Location())
+	if err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+	if !since.IsZero() && !until.IsZero() && since.After(until) {
+		return fmt.Errorf("--since %s is after --until %s", *sinceStr, *untilStr)
+	}
+
+	idx, _ := stats.UpdateIndex(p.ClaudeDir, p.DataDir)
+	cache, err := stats.LoadCache(p.ClaudeDir, idx, now)
+	if err != nil {
+		return err
+	}
+	report := stats.BuildReport(cache, since, until, now)
+
+	if *asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	_, err = fmt.Fprint(w, render.StatsReport(report))
+	return err
+}
+
+// runAchievements achievements 子命令: 列出全部徽章的解锁状态与进度
+// Parameters:
+//   - args: 子命令参数 (--json)
+//   - p: 数据目录
+//   - w: 输出目标
+//
+// Return:
+//   - error: 参数或写入错误
+func runAchievements(args []string, p paths.Paths, w io.Writer) error {
+	fs := flag.NewFlagSet("achievements", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "以 JSON 输出")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	cfg := render.LoadConfig(&p)
+	// 统计数据不可用时仍列出徽章, 只是不显示进度
+	now := time.Now().In(cfg.Location())
+	idx, _ := stats.UpdateIndex(p.ClaudeDir, p.DataDir)
+	info, err := stats.GetStatsInfo(p.ClaudeDir, idx, now)
+	if err != nil {
+		info = nil
+	}
+	metrics := achievement.MetricsFrom(info, nil)
+	unlocked, err := achievement.Evaluate(p.DataDir, metrics, now)
+	if err != nil {
+		return err
+	}
+	catalog := achievement.BuildCatalog(unlocked, metrics)
+
+	if *asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(catalog)
+	}
+	_, err = fmt.Fprint(w, render.AchievementsReport(catalog))
+	return err
+}
+
+// runProjects projects 子命令: 按会话记录输出项目排行
+// Parameters:
+//   - args: 子命令参数 (--sort messages|sessions|cost|recent, --limit N, --json)
+//   - p: 数据目录
+//   - w: 输出目标
+//
+// Return:
+//   - error: 参数或读取错误
+func runProjects(args []string, p paths.Paths, w io.Writer) error {
+	fs := flag.NewFlagSet("projects", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "以 JSON 输出")
+	sortBy := fs.String("sort", stats.ProjectSortMessages, "排序方式: messages/sessions/cost/recent")
+	limit := fs.Int("limit", 0, "只显示前 N 个项目, 0 表示全部")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	switch *sortBy {
+	case stats.ProjectSortMessages, stats.ProjectSortSessions, stats.ProjectSortCost, stats.ProjectSortRecent:
+	default:
+		return fmt.Errorf("invalid --sort %q", *sortBy)
+	}
+
+	cfg := render.LoadConfig(&p)
+	idx, err := stats.UpdateIndex(p.ClaudeDir, p.DataDir)
+	if err != nil {
+		return err
+	}
+	projects := idx.ProjectList(time.Now().In(cfg.Location()), cfg.Prices)
+	stats.SortProjects(projects, *sortBy)
+	if *limit > 0 && len(projects) > *limit {
+		projects = projects[:*limit]
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(projects)
+	}
+	_, err = fmt.Fprint(w, render.ProjectsReport(projects, *sortBy))
+	return err
+}
+
+// runModels models 子命令: 按会话记录输出各模型的用量与成本
+// Parameters:
+//   - args: 子命令参数 (--since YYYY-MM-DD, --until YYYY-MM-DD, --json)
+//   - p: 数据目录
+//   - w: 输出目标
+//
+// Return:
+//   - error: 参数或读取错误
+func runModels(args []string, p paths.Paths, w io.Writer) error {
+	fs := flag.NewFlagSet("models", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "以 JSON 输出")
+	sinceStr := fs.String("since", "", "开始日期 (YYYY-MM-DD), 默认不限")
+	untilStr := fs.String("until", "", "结束日期 (YYYY-MM-DD), 默认不限")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	cfg := render.LoadConfig(&p)
+	loc := cfg.Location()
+	since, err := parseDate(*sinceStr, loc)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	until, err := parseDate(*untilStr, loc)
+	if err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+	if !since.IsZero() && !until.IsZero() && since.After(until) {
+		return fmt.Errorf("--since %s is after --until %s", *sinceStr, *untilStr)
+	}
+
+	idx, err := stats.UpdateIndex(p.ClaudeDir, p.DataDir)
+	if err != nil {
+		return err
+	}
+	models := idx.ModelList(since, until, loc, cfg.Prices)
+
+	if *asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(models)
+	}
+	period := "全部"
+	if *sinceStr != "" || *untilStr != "" {
+		period = strings.TrimSpace(*sinceStr + " ~ " + *untilStr)
+	}
+	_, err = fmt.Fprint(w, render.ModelsReport(models, period))
+	return err
+}
+
+// parseDate 解析 YYYY-MM-DD 日期, 空串返回零值
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, loc)
+}
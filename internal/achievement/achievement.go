@@ -0,0 +1,235 @@
+// Package achievement 成就引擎: 徽章以数据定义 (指标 + 阈值), 解锁后连同日期持久化
+//
+// 徽章按系列 (Track) 分组, 同系列内按等级 (Tier) 递增; 稀有度 (Rarity) 决定 "最稀有" 展示顺序.
+// 统计类徽章 (消息、会话、连续活跃) 解锁后即使指标回落 (如连续中断) 也不会失去.
+package achievement
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/model"
+	"github.com/nyan-statusline-cc/internal/safefile"
+	"github.com/nyan-statusline-cc/internal/stats"
+)
+
+const stateFileName = "nyan-achievements.json"
+
+const dateLayout = "2006-01-02"
+
+// 指标 key, 统计类来自 StatsInfo, 会话类来自当前会话数据
+const (
+	MetricMessages       = "messages"       // 累计消息数
+	MetricSessions       = "sessions"       // 累计会话数
+	MetricStreak         = "streak"         // 当前连续活跃天数
+	MetricActiveDays     = "activeDays"     // 活跃天数
+	MetricSessionLines   = "sessionLines"   // 当前会话新增代码行数
+	MetricSessionMinutes = "sessionMinutes" // 当前会话时长 (分钟)
+	MetricSessionCost    = "sessionCost"    // 当前会话成本 (USD)
+)
+
+// 稀有度
+const (
+	Common = iota + 1
+	Rare
+	Epic
+	Legendary
+)
+
+// Badge 徽章定义
+type Badge struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Icon      string  `json:"icon"`
+	Desc      string  `json:"description"` // 简短描述
+	Track     string  `json:"track"`       // 所属系列, 同系列徽章使用同一指标
+	Tier      int     `json:"tier"`        // 系列内等级, 从 1 开始
+	Metric    string  `json:"metric"`      // 解锁条件的指标 key
+	Threshold float64 `json:"threshold"`   // 指标达到该值解锁
+	Rarity    int     `json:"rarity"`      // 稀有度 (Common ~ Legendary)
+}
+
+// Badges 全部徽章定义 (按展示顺序)
+var Badges = []Badge{
+	{ID: "messages-100", Name: "话唠新星", Icon: "🥈", Desc: "和 Claude 聊开了", Track: "messages", Tier: 1, Metric: MetricMessages, Threshold: 100, Rarity: Common},
+	{ID: "messages-500", Name: "消息达人", Icon: "🥇", Desc: "消息如流水", Track: "messages", Tier: 2, Metric: MetricMessages, Threshold: 500, Rarity: Rare},
+	{ID: "messages-1000", Name: "千言万语", Icon: "🏆", Desc: "说过的话能写一本书", Track: "messages", Tier: 3, Metric: MetricMessages, Threshold: 1000, Rarity: Epic},
+	{ID: "sessions-50", Name: "会话专家", Icon: "⭐", Desc: "开过很多次新会话", Track: "sessions", Tier: 1, Metric: MetricSessions, Threshold: 50, Rarity: Rare},
+	{ID: "sessions-100", Name: "会话之王", Icon: "👑", Desc: "会话次数破百", Track: "sessions", Tier: 2, Metric: MetricSessions, Threshold: 100, Rarity: Epic},
+	{ID: "streak-3", Name: "三连击", Icon: "✊", Desc: "连续三天不间断", Track: "streak", Tier: 1, Metric: MetricStreak, Threshold: 3, Rarity: Common},
+	{ID: "streak-7", Name: "周度坚持", Icon: "💪", Desc: "整整一周天天编码", Track: "streak", Tier: 2, Metric: MetricStreak, Threshold: 7, Rarity: Rare},
+	{ID: "streak-30", Name: "月度坚持", Icon: "🔥", Desc: "一个月从未缺席", Track: "streak", Tier: 3, Metric: MetricStreak, Threshold: 30, Rarity: Legendary},
+	{ID: "active-30", Name: "老用户", Icon: "🎖️", Desc: "累计活跃一个月", Track: "activeDays", Tier: 1, Metric: MetricActiveDays, Threshold: 30, Rarity: Rare},
+	{ID: "session-lines-1000", Name: "代码瀑布", Icon: "🌊", Desc: "一个会话写下千行代码", Track: "sessionLines", Tier: 1, Metric: MetricSessionLines, Threshold: 1000, Rarity: Rare},
+	{ID: "session-marathon", Name: "马拉松", Icon: "🏃", Desc: "一个会话持续两小时", Track: "sessionMinutes", Tier: 1, Metric: MetricSessionMinutes, Threshold: 120, Rarity: Rare},
+	{ID: "session-cost-10", Name: "一掷千金", Icon: "💎", Desc: "一个会话花费 10 美元", Track: "sessionCost", Tier: 1, Metric: MetricSessionCost, Threshold: 10, Rarity: Epic},
+}
+
+// 展示模式
+const (
+	DisplayNewest = "newest" // 最近解锁
+	DisplayRarest = "rarest" // 最稀有
+	DisplayRotate = "rotate" // 轮播
+)
+
+// Metrics 指标 key → 当前值
+type Metrics map[string]float64
+
+// MetricsFrom 从统计摘要和当前会话数据收集指标, 任一参数可为 nil
+// Parameters:
+//   - info: 统计摘要
+//   - session: 当前会话数据
+//
+// Return:
+//   - Metrics: 指标
+func MetricsFrom(info *model.StatsInfo, session *model.SessionData) Metrics {
+	m := Metrics{}
+	if info != nil {
+		m[MetricMessages] = float64(info.TotalMessages)
+		m[MetricSessions] = float64(info.TotalSessions)
+		m[MetricStreak] = float64(info.Streak)
+		m[MetricActiveDays] = float64(info.ActiveDays)
+	}
+	if session != nil {
+		m[MetricSessionLines] = float64(session.Cost.TotalLinesAdded)
+		m[MetricSessionMinutes] = float64(session.Cost.TotalDurationMs) / 60000
+		m[MetricSessionCost] = session.Cost.TotalCostUSD
+	}
+	return m
+}
+
+// Met 判断指标是否满足徽章条件
+func (b Badge) Met(m Metrics) bool {
+	return m[b.Metric] >= b.Threshold
+}
+
+// Unlocked 已解锁的徽章
+type Unlocked struct {
+	Badge
+	Date string `json:"date"` // 解锁日期 (YYYY-MM-DD)
+}
+
+// Text 徽章显示文本, 如 "🏆 千言万语"
+func (u Unlocked) Text() string {
+	return u.Icon + " " + u.Name
+}
+
+// stateData 解锁记录文件结构
+type stateData struct {
+	Unlocked map[string]string `json:"unlocked"` // 徽章 ID → 解锁日期
+}
+
+// loadState 读取解锁记录, 文件不存在或损坏时返回空记录 (损坏时 corrupt 为 true)
+func loadState(dir string) (s stateData, corrupt bool) {
+	if raw, err := os.ReadFile(filepath.Join(dir, stateFileName)); err == nil {
+		corrupt = json.Unmarshal(raw, &s) != nil
+	}
+	if s.Unlocked == nil {
+		s.Unlocked = make(map[string]string)
+	}
+	return s, corrupt
+}
+
+// Load 读取已解锁的徽章 (按定义顺序, 忽略已移除的徽章)
+// Parameters:
+//   - dir: 解锁记录所在目录
+//
+// Return:
+//   - []Unlocked: 已解锁徽章
+func Load(dir string) []Unlocked {
+	s, _ := loadState(dir)
+	return unlockedOf(s)
+}
+
+// Evaluate 按当前指标解锁新徽章, 有新解锁时保存
+// Parameters:
+//   - dir: 解锁记录所在目录
+//   - m: 当前指标
+//   - now: 当前时间, 解锁日期按其时区与统计日起始小时划分
+//
+// Return:
+//   - []Unlocked: 全部已解锁徽章
+//   - error: 写入错误
+func Evaluate(dir string, m Metrics, now time.Time) ([]Unlocked, error) {
+	s, corrupt := loadState(dir)
+	changed := false
+	for _, b := range Badges {
+		if _, ok := s.Unlocked[b.ID]; !ok && b.Met(m) {
+			s.Unlocked[b.ID] = stats.Today(now).Format(dateLayout)
+			changed = true
+		}
+	}
+	if !changed {
+		return unlockedOf(s), nil
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return unlockedOf(s), err
+	}
+	path := filepath.Join(dir, stateFileName)
+	// 损坏的记录改名保留, 避免覆盖原有解锁日期
+	if corrupt {
+		if err := safefile.Quarantine(path); err != nil {
+			return unlockedOf(s), err
+		}
+	}
+	return unlockedOf(s), safefile.Write(path, data)
+}
+
+// unlockedOf 将解锁记录转换为徽章列表
+func unlockedOf(s stateData) []Unlocked {
+	var list []Unlocked
+	for _, b := range Badges {
+		if date, ok := s.Unlocked[b.ID]; ok {
+			list = append(list, Unlocked{Badge: b, Date: date})
+		}
+	}
+	return list
+}
+
+// Pick 按展示模式选出一个徽章
+// newest: 解锁日期最新, 同日取稀有度最高; rarest: 稀有度最高, 同级取最新;
+// rotate: 按 rotate 周期轮流展示全部已解锁徽章. 未知模式按 newest 处理
+// Parameters:
+//   - unlocked: 已解锁徽章
+//   - mode: 展示模式
+//   - now: 当前时间 (轮播)
+//   - rotate: 轮播周期, <= 0 时为 10 秒
+//
+// Return:
+//   - *Unlocked: 选中的徽章, 无已解锁徽章时返回 nil
+func Pick(unlocked []Unlocked, mode string, now time.Time, rotate time.Duration) *Unlocked {
+	if len(unlocked) == 0 {
+		return nil
+	}
+	list := make([]Unlocked, len(unlocked))
+	copy(list, unlocked)
+
+	if mode == DisplayRotate {
+		if rotate <= 0 {
+			rotate = 10 * time.Second
+		}
+		idx := int(now.UnixMilli()/rotate.Milliseconds()) % len(list)
+		return &list[idx]
+	}
+
+	// 稳定排序: 完全同分时保持定义顺序
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if mode == DisplayRarest && a.Rarity != b.Rarity {
+			return a.Rarity > b.Rarity
+		}
+		if a.Date != b.Date {
+			return a.Date > b.Date
+		}
+		if a.Rarity != b.Rarity {
+			return a.Rarity > b.Rarity
+		}
+		return a.Tier > b.Tier
+	})
+	return &list[0]
+}
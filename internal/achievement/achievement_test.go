@@ -0,0 +1,180 @@
+package achievement
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/model"
+	"github.com/nyan-statusline-cc/internal/stats"
+)
+
+var testNow = time.Date(2026, 2, 26, 10, 0, 0, 0, time.UTC)
+
+// badgeTexts 返回解锁徽章的显示文本
+func badgeTexts(list []Unlocked) []string {
+	texts := make([]string, len(list))
+	for i, u := range list {
+		texts[i] = u.Text()
+	}
+	return texts
+}
+
+// TestBadges_Definitions 徽章 ID 唯一, 同系列指标一致且等级、阈值递增
+func TestBadges_Definitions(t *testing.T) {
+	ids := make(map[string]bool)
+	last := make(map[string]Badge)
+	for _, b := range Badges {
+		if ids[b.ID] {
+			t.Errorf("duplicate badge id %q", b.ID)
+		}
+		ids[b.ID] = true
+		if b.Rarity < Common || b.Rarity > Legendary {
+			t.Errorf("badge %q rarity %d out of range", b.ID, b.Rarity)
+		}
+		if prev, ok := last[b.Track]; ok {
+			if prev.Metric != b.Metric || b.Tier != prev.Tier+1 || b.Threshold <= prev.Threshold {
+				t.Errorf("badge %q does not follow %q in track %q", b.ID, prev.ID, b.Track)
+			}
+		} else if b.Tier != 1 {
+			t.Errorf("first badge of track %q should be tier 1, got %d", b.Track, b.Tier)
+		}
+		last[b.Track] = b
+	}
+}
+
+// TestMetricsFrom 统计指标与会话指标
+func TestMetricsFrom(t *testing.T) {
+	info := &model.StatsInfo{TotalMessages: 120, TotalSessions: 4, Streak: 3, ActiveDays: 9}
+	session := &model.SessionData{Cost: model.CostInfo{TotalLinesAdded: 10, TotalDurationMs: 90 * 60000, TotalCostUSD: 2.5}}
+
+	m := MetricsFrom(info, session)
+	if m[MetricMessages] != 120 || m[MetricStreak] != 3 || m[MetricSessionMinutes] != 90 || m[MetricSessionCost] != 2.5 {
+		t.Errorf("MetricsFrom() = %v", m)
+	}
+	if m := MetricsFrom(nil, nil); len(m) != 0 {
+		t.Errorf("MetricsFrom(nil, nil) = %v, want empty", m)
+	}
+}
+
+// TestEvaluate_Thresholds 达到阈值解锁, 与原有成就条件一致
+func TestEvaluate_Thresholds(t *testing.T) {
+	cases := []struct {
+		name string
+		info model.StatsInfo
+		want []string
+	}{
+		{"none", model.StatsInfo{TotalMessages: 99, TotalSessions: 49, Streak: 2, ActiveDays: 29}, nil},
+		{"messages_500", model.StatsInfo{TotalMessages: 500}, []string{"🥈 话唠新星", "🥇 消息达人"}},
+		{"sessions_100", model.StatsInfo{TotalSessions: 100}, []string{"⭐ 会话专家", "👑 会话之王"}},
+		{"streak_7", model.StatsInfo{Streak: 7}, []string{"✊ 三连击", "💪 周度坚持"}},
+		{"active_30", model.StatsInfo{ActiveDays: 30}, []string{"🎖️ 老用户"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Evaluate(t.TempDir(), MetricsFrom(&tc.info, nil), testNow)
+			if err != nil {
+				t.Fatalf("Evaluate() error: %v", err)
+			}
+			texts := badgeTexts(got)
+			if len(texts) != len(tc.want) {
+				t.Fatalf("Evaluate() = %v, want %v", texts, tc.want)
+			}
+			for i := range texts {
+				if texts[i] != tc.want[i] {
+					t.Errorf("Evaluate()[%d] = %q, want %q", i, texts[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestEvaluate_Persists 解锁日期持久化, 指标回落后徽章仍保留
+func TestEvaluate_Persists(t *testing.T) {
+	dir := t.TempDir()
+	_, _ = Evaluate(dir, Metrics{MetricStreak: 3}, testNow)
+
+	later := testNow.AddDate(0, 0, 5)
+	got, _ := Evaluate(dir, Metrics{MetricStreak: 0, MetricMessages: 100}, later)
+	if len(got) != 2 {
+		t.Fatalf("Evaluate() = %v, want streak-3 kept and messages-100 unlocked", badgeTexts(got))
+	}
+	dates := map[string]string{}
+	for _, u := range Load(dir) {
+		dates[u.ID] = u.Date
+	}
+	if dates["streak-3"] != "2026-02-26" || dates["messages-100"] != "2026-03-03" {
+		t.Errorf("unlock dates = %v", dates)
+	}
+}
+
+// TestEvaluate_Corrupt 解锁记录损坏时改名保留, 不直接覆盖
+func TestEvaluate_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, stateFileName)
+	if err := os.WriteFile(path, []byte(`{"unlocked":{"streak-3":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Evaluate(dir, Metrics{MetricStreak: 3}, testNow); err != nil {
+		t.Fatalf("Evaluate() error: %v", err)
+	}
+	if raw, _ := os.ReadFile(path + ".corrupt"); string(raw) != `{"unlocked":{"streak-3":` {
+		t.Errorf("backup = %q, want the corrupt record kept", raw)
+	}
+	if got := Load(dir); len(got) != 1 {
+		t.Errorf("Load() = %v, want streak-3 saved", badgeTexts(got))
+	}
+}
+
+// TestEvaluate_DayStart 解锁日期按时区与统计日起始小时划分
+func TestEvaluate_DayStart(t *testing.T) {
+	stats.SetDayStart(4)
+	t.Cleanup(func() { stats.SetDayStart(0) })
+
+	dir := t.TempDir()
+	// 上海时间 2026-02-27 03:00, 起始小时 4 点前仍算 2-26
+	now := time.Date(2026, 2, 26, 19, 0, 0, 0, time.UTC).In(time.FixedZone("CST", 8*3600))
+	_, _ = Evaluate(dir, Metrics{MetricStreak: 3}, now)
+	if got := Load(dir); len(got) != 1 || got[0].Date != "2026-02-26" {
+		t.Errorf("Load() = %+v, want unlocked on 2026-02-26", got)
+	}
+}
+
+// TestPick 最近解锁/最稀有/轮播
+func TestPick(t *testing.T) {
+	byID := map[string]Badge{}
+	for _, b := range Badges {
+		byID[b.ID] = b
+	}
+	unlocked := []Unlocked{
+		{Badge: byID["messages-100"], Date: "2026-01-01"},
+		{Badge: byID["streak-30"], Date: "2026-01-10"},
+		{Badge: byID["sessions-50"], Date: "2026-02-01"},
+		{Badge: byID["active-30"], Date: "2026-02-01"},
+	}
+
+	if got := Pick(nil, DisplayNewest, testNow, 0); got != nil {
+		t.Errorf("Pick(nil) = %+v, want nil", got)
+	}
+	// 同日解锁时取稀有度高者, 同稀有度保持定义顺序
+	if got := Pick(unlocked, DisplayNewest, testNow, 0); got.ID != "sessions-50" {
+		t.Errorf("newest = %q, want sessions-50", got.ID)
+	}
+	if got := Pick(unlocked, DisplayRarest, testNow, 0); got.ID != "streak-30" {
+		t.Errorf("rarest = %q, want streak-30", got.ID)
+	}
+	if got := Pick(unlocked, "unknown", testNow, 0); got.ID != "sessions-50" {
+		t.Errorf("unknown mode = %q, want newest", got.ID)
+	}
+
+	seen := map[string]bool{}
+	for i := range len(unlocked) {
+		at := time.UnixMilli(int64(i) * 5000)
+		seen[Pick(unlocked, DisplayRotate, at, 5*time.Second).ID] = true
+	}
+	if len(seen) != len(unlocked) {
+		t.Errorf("rotate showed %d badges, want all %d", len(seen), len(unlocked))
+	}
+}
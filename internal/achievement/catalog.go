@@ -0,0 +1,78 @@
+package achievement
+
+import (
+	"strconv"
+
+	"github.com/nyan-statusline-cc/internal/formatter"
+)
+
+// metricConditions 指标在解锁条件中的名称与单位
+var metricConditions = map[string][2]string{
+	MetricMessages:       {"累计消息", "条"},
+	MetricSessions:       {"累计会话", "个"},
+	MetricStreak:         {"连续活跃", "天"},
+	MetricActiveDays:     {"活跃天数", "天"},
+	MetricSessionLines:   {"单会话新增代码", "行"},
+	MetricSessionMinutes: {"单会话时长", "分钟"},
+	MetricSessionCost:    {"单会话花费", " USD"},
+}
+
+// Condition 解锁条件文本, 如 "累计消息 ≥ 100条"
+func (b Badge) Condition() string {
+	c := metricConditions[b.Metric]
+	threshold := strconv.FormatFloat(b.Threshold, 'f', -1, 64)
+	if b.Threshold == float64(int64(b.Threshold)) {
+		threshold = formatter.FormatCount(int64(b.Threshold))
+	}
+	return c[0] + " ≥ " + threshold + c[1]
+}
+
+// Status 徽章的解锁状态
+type Status struct {
+	Badge
+	Condition string   `json:"condition"`
+	Unlocked  bool     `json:"unlocked"`
+	Date      string   `json:"date,omitempty"`    // 解锁日期
+	Current   *float64 `json:"current,omitempty"` // 当前指标值, 指标不可用 (如无会话数据) 时为空
+	Progress  float64  `json:"progress"`          // 完成比例 (0-1), 已解锁为 1
+}
+
+// Catalog 全部徽章及整体完成度
+type Catalog struct {
+	Unlocked      int      `json:"unlocked"`
+	Total         int      `json:"total"`
+	CompletionPct float64  `json:"completion_pct"`
+	Badges        []Status `json:"badges"`
+}
+
+// BuildCatalog 汇总全部徽章的解锁状态与进度
+// Parameters:
+//   - unlocked: 已解锁徽章
+//   - m: 当前指标, 缺少的指标不显示进度
+//
+// Return:
+//   - *Catalog: 按定义顺序的徽章状态
+func BuildCatalog(unlocked []Unlocked, m Metrics) *Catalog {
+	dates := make(map[string]string, len(unlocked))
+	for _, u := range unlocked {
+		dates[u.ID] = u.Date
+	}
+
+	c := &Catalog{Total: len(Badges)}
+	for _, b := range Badges {
+		s := Status{Badge: b, Condition: b.Condition()}
+		if v, ok := m[b.Metric]; ok {
+			s.Current = &v
+			s.Progress = Progress{Next: b, Current: v}.Ratio()
+		}
+		if date, ok := dates[b.ID]; ok {
+			s.Unlocked, s.Date, s.Progress = true, date, 1
+			c.Unlocked++
+		}
+		c.Badges = append(c.Badges, s)
+	}
+	if c.Total > 0 {
+		c.CompletionPct = float64(c.Unlocked) / float64(c.Total) * 100
+	}
+	return c
+}
@@ -0,0 +1,47 @@
+package achievement
+
+import "testing"
+
+// TestCondition 解锁条件文本
+func TestCondition(t *testing.T) {
+	cases := map[string]string{
+		"messages-1000":    "累计消息 ≥ 1000条",
+		"streak-7":         "连续活跃 ≥ 7天",
+		"session-marathon": "单会话时长 ≥ 120分钟",
+		"session-cost-10":  "单会话花费 ≥ 10 USD",
+	}
+	for _, b := range Badges {
+		if want, ok := cases[b.ID]; ok && b.Condition() != want {
+			t.Errorf("%s.Condition() = %q, want %q", b.ID, b.Condition(), want)
+		}
+	}
+}
+
+// TestBuildCatalog 解锁状态、进度与完成度
+func TestBuildCatalog(t *testing.T) {
+	m := Metrics{MetricMessages: 250, MetricSessions: 10, MetricStreak: 3, MetricActiveDays: 5}
+	unlocked, _ := Evaluate(t.TempDir(), m, testNow)
+	c := BuildCatalog(unlocked, m)
+
+	if c.Total != len(Badges) || c.Unlocked != 2 || len(c.Badges) != len(Badges) {
+		t.Fatalf("BuildCatalog() = %d/%d with %d badges", c.Unlocked, c.Total, len(c.Badges))
+	}
+	if want := 2.0 / float64(len(Badges)) * 100; c.CompletionPct != want {
+		t.Errorf("CompletionPct = %.2f, want %.2f", c.CompletionPct, want)
+	}
+
+	byID := map[string]Status{}
+	for _, s := range c.Badges {
+		byID[s.ID] = s
+	}
+	if s := byID["messages-100"]; !s.Unlocked || s.Date != "2026-02-26" || s.Progress != 1 {
+		t.Errorf("messages-100 = %+v, want unlocked on 2026-02-26", s)
+	}
+	if s := byID["messages-500"]; s.Unlocked || s.Current == nil || *s.Current != 250 || s.Progress != 0.5 {
+		t.Errorf("messages-500 = %+v, want locked at 50%%", s)
+	}
+	// 无会话数据时会话类徽章没有进度
+	if s := byID["session-cost-10"]; s.Current != nil || s.Progress != 0 {
+		t.Errorf("session-cost-10 = %+v, want no progress", s)
+	}
+}
@@ -0,0 +1,100 @@
+package achievement
+
+import (
+	"math"
+	"sort"
+
+	"github.com/nyan-statusline-cc/internal/formatter"
+)
+
+// ProgressTracks 显示升级进度的系列 (累计类指标); 会话类徽章每个会话重新计算, 不显示进度
+var ProgressTracks = []string{"messages", "sessions", "streak", "activeDays"}
+
+// metricUnits 指标在进度文本中的单位
+var metricUnits = map[string]string{
+	MetricMessages:   "消息",
+	MetricSessions:   "会话",
+	MetricStreak:     "连",
+	MetricActiveDays: "天",
+}
+
+// 进度展示模式
+const (
+	ProgressNearest = "nearest" // 只显示最接近升级的系列
+	ProgressAll     = "all"     // 显示全部系列
+)
+
+// Progress 某系列距下一等级的进度
+type Progress struct {
+	Track   string  `json:"track"`
+	From    *Badge  `json:"from,omitempty"` // 已解锁的最高等级, 尚未解锁任何等级时为 nil
+	Next    Badge   `json:"next"`           // 下一等级
+	Current float64 `json:"current"`        // 当前指标值
+}
+
+// Ratio 完成比例 (0-1)
+func (p Progress) Ratio() float64 {
+	if p.Next.Threshold <= 0 {
+		return 1
+	}
+	return math.Min(math.Max(p.Current/p.Next.Threshold, 0), 1)
+}
+
+// Text 进度文本, 如 "🥈→🥇 432/500消息"
+func (p Progress) Text() string {
+	icons := p.Next.Icon
+	if p.From != nil {
+		icons = p.From.Icon + "→" + icons
+	}
+	return icons + " " + formatter.FormatCount(int64(p.Current)) + "/" +
+		formatter.FormatCount(int64(p.Next.Threshold)) + metricUnits[p.Next.Metric]
+}
+
+// NextTiers 计算各进度系列距下一等级的进度, 已满级的系列不返回
+// Parameters:
+//   - unlocked: 已解锁徽章
+//   - m: 当前指标
+//
+// Return:
+//   - []Progress: 按 ProgressTracks 顺序的进度
+func NextTiers(unlocked []Unlocked, m Metrics) []Progress {
+	have := make(map[string]bool, len(unlocked))
+	for _, u := range unlocked {
+		have[u.ID] = true
+	}
+
+	var list []Progress
+	for _, track := range ProgressTracks {
+		var from *Badge
+		for _, b := range Badges {
+			if b.Track != track {
+				continue
+			}
+			if have[b.ID] {
+				from = &b
+				continue
+			}
+			list = append(list, Progress{Track: track, From: from, Next: b, Current: m[b.Metric]})
+			break
+		}
+	}
+	return list
+}
+
+// Nearest 返回完成比例最高的进度, 同比例保持原顺序
+// Parameters:
+//   - list: 进度列表
+//
+// Return:
+//   - *Progress: 最接近升级的进度, 列表为空时返回 nil
+func Nearest(list []Progress) *Progress {
+	if len(list) == 0 {
+		return nil
+	}
+	sorted := make([]Progress, len(list))
+	copy(sorted, list)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Ratio() > sorted[j].Ratio()
+	})
+	return &sorted[0]
+}
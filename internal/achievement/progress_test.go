@@ -0,0 +1,38 @@
+package achievement
+
+import "testing"
+
+// TestNextTiers 下一等级与起点徽章, 满级系列不返回
+func TestNextTiers(t *testing.T) {
+	m := Metrics{MetricMessages: 432, MetricSessions: 120, MetricStreak: 2, MetricActiveDays: 12}
+	unlocked, _ := Evaluate(t.TempDir(), Metrics{MetricMessages: 432, MetricSessions: 120, MetricStreak: 5}, testNow)
+
+	got := map[string]Progress{}
+	for _, p := range NextTiers(unlocked, m) {
+		got[p.Track] = p
+	}
+	if _, ok := got["sessions"]; ok {
+		t.Errorf("sessions track is complete, got %+v", got["sessions"])
+	}
+	if p := got["messages"]; p.Next.ID != "messages-500" || p.Text() != "🥈→🥇 432/500消息" {
+		t.Errorf("messages progress = %q (next %s)", p.Text(), p.Next.ID)
+	}
+	// 连续中断后仍以下一未解锁等级为目标
+	if p := got["streak"]; p.Next.ID != "streak-7" || p.Text() != "✊→💪 2/7连" {
+		t.Errorf("streak progress = %q", p.Text())
+	}
+	if p := got["activeDays"]; p.From != nil || p.Text() != "🎖️ 12/30天" {
+		t.Errorf("activeDays progress = %q", p.Text())
+	}
+}
+
+// TestNearest 取完成比例最高的进度
+func TestNearest(t *testing.T) {
+	if Nearest(nil) != nil {
+		t.Error("Nearest(nil) should be nil")
+	}
+	list := NextTiers(nil, Metrics{MetricMessages: 50, MetricSessions: 40, MetricStreak: 1})
+	if got := Nearest(list); got.Track != "sessions" || got.Ratio() != 0.8 {
+		t.Errorf("Nearest() = %s (%.2f), want sessions (0.80)", got.Track, got.Ratio())
+	}
+}
@@ -0,0 +1,95 @@
+package animation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 庆祝动画时长, 结束后回到静止的完成帧
+const (
+	celebrationDuration       = 4 * time.Second
+	recordCelebrationDuration = 8 * time.Second
+)
+
+// sparkleColors 尾巴上迸发的火花颜色 (ANSI 256 色, 金色系)
+var sparkleColors = []int{226, 220, 214, 231}
+
+// celebrationFrames 猫咪绕圈时的表情帧序列
+var celebrationFrames = []string{"😸", "😹", "😻", "😸"}
+
+// Celebration 返回处理完成后的庆祝动画帧
+// 猫咪沿彩虹尾巴跑一圈胜利巡游, 身后的尾巴迸发火花; 刷新纪录时动画更长且带奖杯
+//
+// Parameters:
+//   - elapsed: 距处理完成的时长
+//   - record: 本轮是否刷新纪录
+//
+// Return:
+//   - string: 当前动画帧, 庆祝结束后返回空串
+func Celebration(elapsed time.Duration, record bool) string {
+	limit := celebrationDuration
+	if record {
+		limit = recordCelebrationDuration
+	}
+	if elapsed < 0 || elapsed >= limit {
+		return ""
+	}
+	return celebrationFrameAt(int(elapsed.Milliseconds()/250), record)
+}
+
+// celebrationFrameAt 根据帧索引生成庆祝动画帧
+// Parameters:
+//   - frameIdx: 帧索引, 驱动猫咪在尾巴上的位置、火花闪烁和表情切换
+//   - record: 是否为刷新纪录的加强版
+//
+// Return:
+//   - string: 当前帧的字符串表示
+func celebrationFrameAt(frameIdx int, record bool) string {
+	// 猫咪位置在 [0, n] 间往返, 左侧保留彩虹尾巴, 右侧为火花
+	n := len(rainbow256)
+	pos := frameIdx % (2 * n)
+	if pos > n {
+		pos = 2*n - pos
+	}
+
+	var b strings.Builder
+	for i := range pos {
+		fmt.Fprintf(&b, "\033[38;5;%dm█", rainbow256[(i+frameIdx)%n])
+	}
+	b.WriteString("\033[0m")
+	b.WriteString(celebrationFrames[frameIdx%len(celebrationFrames)])
+	for i := range n - pos {
+		color := sparkleColors[(i+frameIdx)%len(sparkleColors)]
+		glyph := "·"
+		if (i+frameIdx)%3 == 0 {
+			glyph = "*"
+		}
+		fmt.Fprintf(&b, "\033[38;5;%dm%s", color, glyph)
+	}
+	b.WriteString("\033[0m")
+
+	if record {
+		b.WriteString([]string{"🏆", "🎆"}[frameIdx%2])
+	}
+	b.WriteString([]string{"🎉", "✨"}[frameIdx%2])
+	return b.String()
+}
+
+// recordFlashColors 新纪录提示闪烁的两种颜色 (粗体亮黄/粗体白)
+var recordFlashColors = []string{"\033[1;93m", "\033[1;97m"}
+
+// RecordFlash 返回新纪录提示, 动画时每 500ms 切换颜色闪烁
+// Parameters:
+//   - m: 运动参数, 静止时固定为亮黄
+//
+// Return:
+//   - string: 带 ANSI 颜色的 "🏅 new record!"
+func RecordFlash(m Motion) string {
+	return recordFlashAt(m.frameIndex(time.Now(), 500))
+}
+
+// recordFlashAt 根据帧索引生成新纪录提示
+func recordFlashAt(frameIdx int) string {
+	return recordFlashColors[frameIdx%len(recordFlashColors)] + "🏅 new record!\033[0m"
+}
@@ -0,0 +1,71 @@
+package animation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCelebration_Expires 庆祝动画超时后返回空串
+func TestCelebration_Expires(t *testing.T) {
+	if Celebration(0, false) == "" {
+		t.Error("Celebration(0) should return a frame")
+	}
+	if got := Celebration(celebrationDuration, false); got != "" {
+		t.Errorf("Celebration after duration = %q, want empty", got)
+	}
+	if got := Celebration(-time.Second, false); got != "" {
+		t.Errorf("Celebration with negative elapsed = %q, want empty", got)
+	}
+}
+
+// TestCelebration_RecordLasts 刷新纪录时庆祝时间更长
+func TestCelebration_RecordLasts(t *testing.T) {
+	elapsed := celebrationDuration + time.Second
+	if Celebration(elapsed, false) != "" {
+		t.Error("normal celebration should have ended")
+	}
+	if Celebration(elapsed, true) == "" {
+		t.Error("record celebration should still be running")
+	}
+}
+
+// TestCelebrationFrameAt_Trophy 刷新纪录的帧包含奖杯或烟花
+func TestCelebrationFrameAt_Trophy(t *testing.T) {
+	for i := range 4 {
+		frame := celebrationFrameAt(i, true)
+		if !strings.Contains(frame, "🏆") && !strings.Contains(frame, "🎆") {
+			t.Errorf("record frame %d should contain trophy or fireworks, got %q", i, frame)
+		}
+		if strings.Contains(celebrationFrameAt(i, false), "🏆") {
+			t.Errorf("normal frame %d should not contain trophy", i)
+		}
+	}
+}
+
+// TestCelebrationFrameAt_ConstantLength 猫咪巡游时尾巴 + 火花的格数保持不变
+func TestCelebrationFrameAt_ConstantLength(t *testing.T) {
+	for i := range 2 * len(rainbow256) {
+		frame := celebrationFrameAt(i, false)
+		cells := strings.Count(frame, "█") + strings.Count(frame, "·") + strings.Count(frame, "*")
+		if cells != len(rainbow256) {
+			t.Errorf("frame %d: tail+sparkle cells = %d, want %d", i, cells, len(rainbow256))
+		}
+	}
+}
+
+// TestRecordFlash 新纪录提示在两种颜色间交替, 静止时固定为第一帧
+func TestRecordFlash(t *testing.T) {
+	if recordFlashAt(0) == recordFlashAt(1) {
+		t.Error("record flash frames should alternate")
+	}
+	if recordFlashAt(0) != recordFlashAt(2) {
+		t.Error("record flash should loop every two frames")
+	}
+	if got := RecordFlash(Motion{Level: MotionOff}); got != recordFlashAt(0) {
+		t.Errorf("static RecordFlash() = %q, want first frame", got)
+	}
+	if !strings.Contains(recordFlashAt(1), "🏅 new record!") {
+		t.Error("record flash should contain the badge text")
+	}
+}
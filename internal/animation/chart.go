@@ -0,0 +1,106 @@
+package animation
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// sparkLevels 迷你图字符, 按高度递增, 视觉宽度均为 1
+var sparkLevels = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+// Sparkline 生成迷你趋势图, 每个值一格, 按最大值等比缩放
+// Parameters:
+//   - values: 数据序列 (按时间先后)
+//
+// Return:
+//   - string: 趋势图字符串 (无颜色), 0 显示为最低格
+func Sparkline(values []int) string {
+	maxV := 0
+	for _, v := range values {
+		maxV = max(maxV, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		b.WriteString(sparkLevels[sparkLevel(v, maxV)])
+	}
+	return b.String()
+}
+
+// HourHeatmap 生成 24 格时段热力图, 高度与颜色随活跃度变化 (紫 → 红)
+// Parameters:
+//   - counts: 0-23 点的计数
+//
+// Return:
+//   - string: 带 ANSI 256 色的热力图, 无活动的时段显示为灰点
+func HourHeatmap(counts [24]int) string {
+	maxV := 0
+	for _, v := range counts {
+		maxV = max(maxV, v)
+	}
+	var b strings.Builder
+	for _, v := range counts {
+		level := sparkLevel(v, maxV)
+		if level == 0 {
+			b.WriteString("\033[90m·")
+			continue
+		}
+		b.WriteString(heatColor(level) + sparkLevels[level])
+	}
+	b.WriteString("\033[0m")
+	return b.String()
+}
+
+// CalendarHeatmap 生成按周排列的日历热力图, 每列一周, 颜色随活跃度变化 (紫 → 红)
+// Parameters:
+//   - values: 从 start 开始的每日计数
+//   - start: 第一天的日期
+//
+// Return:
+//   - []string: 7 行 (周一至周日), 区间外为空格, 无活动为灰点, 每行以颜色重置结尾
+func CalendarHeatmap(values []int, start time.Time) []string {
+	maxV := 0
+	for _, v := range values {
+		maxV = max(maxV, v)
+	}
+	lead := (int(start.Weekday()) + 6) % 7 // 周一为 0
+	weeks := (lead + len(values) + 6) / 7
+
+	rows := make([]string, 7)
+	for wd := range 7 {
+		var b strings.Builder
+		for w := range weeks {
+			i := w*7 + wd - lead
+			if i < 0 || i >= len(values) {
+				b.WriteString(" ")
+				continue
+			}
+			if level := sparkLevel(values[i], maxV); level > 0 {
+				b.WriteString(heatColor(level) + "■")
+			} else {
+				b.WriteString("\033[90m·")
+			}
+		}
+		b.WriteString("\033[0m")
+		rows[wd] = b.String()
+	}
+	return rows
+}
+
+// heatColor 返回活跃度等级 (1 至最高) 对应的前景色, 等级 1 为紫色 (最冷), 最高等级为红色 (最热)
+func heatColor(level int) string {
+	n := len(rainbow256)
+	top := len(sparkLevels) - 1
+	colorIdx := (n - 1) - (level-1)*(n-1)/(top-1)
+	return fmt.Sprintf("\033[38;5;%dm", rainbow256[colorIdx])
+}
+
+// sparkLevel 返回 v 相对 maxV 的高度等级, 0 仅用于无数据, 非零值至少为 1
+func sparkLevel(v, maxV int) int {
+	if v <= 0 || maxV <= 0 {
+		return 0
+	}
+	top := len(sparkLevels) - 1
+	return max(1, min(int(math.Ceil(float64(v)/float64(maxV)*float64(top))), top))
+}
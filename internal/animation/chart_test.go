@@ -0,0 +1,69 @@
+package animation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSparkline(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []int
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"all_zero", []int{0, 0, 0}, "▁▁▁"},
+		{"scaled", []int{0, 7, 14, 3}, "▁▅█▃"},
+		{"small_nonzero", []int{1, 100}, "▂█"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Sparkline(tc.values); got != tc.want {
+				t.Errorf("Sparkline(%v) = %q, want %q", tc.values, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHourHeatmap(t *testing.T) {
+	var counts [24]int
+	counts[9] = 1
+	counts[14] = 10
+
+	got := HourHeatmap(counts)
+	plain := stripColor(got)
+	if n := len([]rune(plain)); n != 24 {
+		t.Fatalf("HourHeatmap() has %d cells, want 24: %q", n, plain)
+	}
+	if r := []rune(plain); r[0] != '·' || r[9] != '▂' || r[14] != '█' {
+		t.Errorf("HourHeatmap() = %q, want · at 0, ▂ at 9, █ at 14", plain)
+	}
+	// 最活跃时段为红色, 最低等级为紫色
+	if !strings.Contains(got, "\033[38;5;196m█") || !strings.Contains(got, "\033[38;5;93m▂") {
+		t.Errorf("HourHeatmap() colors = %q", got)
+	}
+	if !strings.HasSuffix(got, "\033[0m") {
+		t.Error("HourHeatmap() should reset color at the end")
+	}
+}
+
+func TestCalendarHeatmap(t *testing.T) {
+	// 2026-02-25 为周三, 共 7 天跨两周
+	start := time.Date(2026, 2, 25, 0, 0, 0, 0, time.UTC)
+	rows := CalendarHeatmap([]int{1, 0, 0, 0, 0, 0, 9}, start)
+	if len(rows) != 7 {
+		t.Fatalf("CalendarHeatmap() returned %d rows, want 7", len(rows))
+	}
+
+	want := []string{" ·", " ■", "■ ", "· ", "· ", "· ", "· "}
+	for wd, row := range rows {
+		if got := stripColor(row); got != want[wd] {
+			t.Errorf("row %d = %q, want %q", wd, got, want[wd])
+		}
+	}
+	if !strings.Contains(rows[1], "\033[38;5;196m■") {
+		t.Errorf("busiest day should be red, got %q", rows[1])
+	}
+}
@@ -1,15 +1,11 @@
 package animation
 
-import (
-	"fmt"
-	"strings"
-	"time"
-)
+import "time"
 
 // ANSI 256 色彩虹色值
 var rainbow256 = []int{196, 208, 226, 46, 51, 21, 93}
 
-// RainbowProgressBar 生成彩虹渐变进度条
+// RainbowProgressBar 生成彩虹渐变进度条 (八分之一块精度)
 // Parameters:
 //   - percent: 百分比 (0-100)
 //   - width: 进度条字符宽度
@@ -17,21 +13,7 @@ var rainbow256 = []int{196, 208, 226, 46, 51, 21, 93}
 // Return:
 //   - string: 带 ANSI 彩虹色的进度条字符串
 func RainbowProgressBar(percent float64, width int) string {
-	if width <= 0 {
-		width = 10
-	}
-	filled := min(int(float64(width)*percent/100), width)
-
-	var b strings.Builder
-	for i := range filled {
-		colorIdx := min(i*len(rainbow256)/width, len(rainbow256)-1)
-		fmt.Fprintf(&b, "\033[38;5;%dm█", rainbow256[colorIdx])
-	}
-	for range width - filled {
-		b.WriteString("\033[90m░")
-	}
-	b.WriteString("\033[0m")
-	return b.String()
+	return ProgressBar(percent, BarOptions{Width: width})
 }
 
 // heartbeatFrames 随机动画帧序列
@@ -48,7 +30,17 @@ var statusMessages = []string{
 // Return:
 //   - string: 心跳 emoji
 func Heartbeat() string {
-	idx := int(time.Now().UnixMilli()/333) % len(heartbeatFrames)
+	return HeartbeatWith(Motion{})
+}
+
+// HeartbeatWith 按运动参数返回心跳动画帧 (1 倍速约 3fps)
+// Parameters:
+//   - m: 运动参数, 静止时返回首帧
+//
+// Return:
+//   - string: 心跳 emoji
+func HeartbeatWith(m Motion) string {
+	idx := m.frameIndex(time.Now(), 333) % len(heartbeatFrames)
 	return heartbeatFrames[idx]
 }
 
@@ -56,6 +48,16 @@ func Heartbeat() string {
 // Return:
 //   - string: 状态文字
 func RandomStatus() string {
-	idx := int(time.Now().Unix()/60) % len(statusMessages)
+	return RandomStatusWith(Motion{})
+}
+
+// RandomStatusWith 按运动参数返回随机状态文字 (1 倍速每分钟轮换)
+// Parameters:
+//   - m: 运动参数, 静止时返回第一条
+//
+// Return:
+//   - string: 状态文字
+func RandomStatusWith(m Motion) string {
+	idx := m.frameIndex(time.Now(), 60000) % len(statusMessages)
 	return statusMessages[idx]
 }
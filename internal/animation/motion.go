@@ -0,0 +1,36 @@
+package animation
+
+import "time"
+
+// MotionLevel 全局动画级别
+type MotionLevel string
+
+// 动画级别取值
+const (
+	MotionFull    MotionLevel = "full"    // 完整动画
+	MotionReduced MotionLevel = "reduced" // 减弱动画: 静止帧, 仍体现状态 (心情/尾巴长度)
+	MotionOff     MotionLevel = "off"     // 关闭动画: 静止帧, 纯装饰性动画由调用方隐藏
+)
+
+// Motion 单个动画的运动参数, 零值表示完整动画、1 倍速
+type Motion struct {
+	Level MotionLevel // 空值等同 MotionFull
+	Speed float64     // 速度倍率, <= 0 时按 1 倍速
+}
+
+// Animated 是否播放动画 (否则使用静止帧)
+func (m Motion) Animated() bool {
+	return m.Level == "" || m.Level == MotionFull
+}
+
+// frameIndex 按帧周期和速度倍率计算指定时刻的帧索引, 静止时恒为 0
+func (m Motion) frameIndex(now time.Time, periodMs int64) int {
+	if !m.Animated() || periodMs <= 0 {
+		return 0
+	}
+	speed := m.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	return int(float64(now.UnixMilli()) * speed / float64(periodMs))
+}
@@ -0,0 +1,71 @@
+package animation
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMotion_Animated 空值和 full 播放动画, reduced/off 使用静止帧
+func TestMotion_Animated(t *testing.T) {
+	tests := []struct {
+		level MotionLevel
+		want  bool
+	}{
+		{"", true},
+		{MotionFull, true},
+		{MotionReduced, false},
+		{MotionOff, false},
+	}
+	for _, tt := range tests {
+		if got := (Motion{Level: tt.level}).Animated(); got != tt.want {
+			t.Errorf("Motion{%q}.Animated() = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+// TestMotion_FrameIndex 速度倍率缩放帧索引, 静止时恒为 0
+func TestMotion_FrameIndex(t *testing.T) {
+	now := time.UnixMilli(10000)
+	tests := []struct {
+		name string
+		m    Motion
+		want int
+	}{
+		{"default speed", Motion{}, 40},
+		{"double speed", Motion{Speed: 2}, 80},
+		{"half speed", Motion{Speed: 0.5}, 20},
+		{"negative speed", Motion{Speed: -1}, 40},
+		{"reduced", Motion{Level: MotionReduced, Speed: 2}, 0},
+		{"off", Motion{Level: MotionOff}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.m.frameIndex(now, 250); got != tt.want {
+			t.Errorf("%s: frameIndex = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestNyanFrameWith_Reduced 减弱动画时使用首帧, 保留尾巴长度和心情, 不闪烁
+func TestNyanFrameWith_Reduced(t *testing.T) {
+	opts := NyanOptions{Mood: MoodSweating, TailLen: 3, Flicker: true, Motion: Motion{Level: MotionReduced}}
+	for range 3 {
+		want := renderNyanFrame(0, NyanOptions{Mood: MoodSweating, TailLen: 3})
+		if got := NyanFrameWith(opts); got != want {
+			t.Errorf("reduced NyanFrameWith = %q, want static frame %q", got, want)
+		}
+	}
+}
+
+// TestHeartbeatWith_Static 静止时始终返回首帧
+func TestHeartbeatWith_Static(t *testing.T) {
+	if got := HeartbeatWith(Motion{Level: MotionReduced}); got != heartbeatFrames[0] {
+		t.Errorf("HeartbeatWith(reduced) = %q, want %q", got, heartbeatFrames[0])
+	}
+}
+
+// TestRandomStatusWith_Static 静止时始终返回第一条状态
+func TestRandomStatusWith_Static(t *testing.T) {
+	if got := RandomStatusWith(Motion{Level: MotionOff}); got != statusMessages[0] {
+		t.Errorf("RandomStatusWith(off) = %q, want %q", got, statusMessages[0])
+	}
+}
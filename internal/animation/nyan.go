@@ -6,12 +6,42 @@ import (
 	"time"
 )
 
+// Mood 猫咪心情, 决定 Nyan Cat 使用的表情帧序列
+type Mood string
+
+// 猫咪心情取值
+const (
+	MoodNormal      Mood = "normal"      // 默认
+	MoodFocused     Mood = "focused"     // 处理中
+	MoodCelebrating Mood = "celebrating" // 处理完成
+	MoodSleepy      Mood = "sleepy"      // 长时间空闲
+	MoodSweating    Mood = "sweating"    // 上下文即将用尽
+)
+
 // catFrames 猫咪帧序列, 交替使用不同 emoji 表示动感
 var catFrames = []string{"🐱", "😺", "🐱", "😸"}
 
+// moodFrames 各心情对应的猫咪帧序列
+var moodFrames = map[Mood][]string{
+	MoodNormal:      catFrames,
+	MoodFocused:     {"😼", "🐱", "😼", "🐱"},
+	MoodCelebrating: {"😸", "😻", "😹", "😻"},
+	MoodSleepy:      {"😴", "😴", "😪", "💤"},
+	MoodSweating:    {"😰", "🙀", "😰", "😿"},
+}
+
 // starFrames 星星点缀帧序列
 var starFrames = []string{"✨", "⭐", "✨"}
 
+// NyanOptions Nyan Cat 帧参数
+type NyanOptions struct {
+	Mood    Mood    // 猫咪心情, 空值等同 MoodNormal
+	TailLen int     // 彩虹尾巴长度 (格), 0 或超出范围时使用完整 7 格
+	Flicker bool    // 尾巴闪烁: 奇数帧尾巴变灰
+	Sprite  *Sprite // 自定义精灵, 非 nil 时替换猫咪和星星 (心情不再影响表情)
+	Motion  Motion  // 运动参数, 静止时使用首帧且不闪烁
+}
+
 // NyanFrame 返回当前 Nyan Cat 动画帧
 // 猫咪使用 emoji, 彩虹尾巴使用 ANSI 256 色 7 色方案 (与 NyanProgressBar 一致)
 // 颜色每帧偏移一位, 产生流畅的滚动效果
@@ -19,31 +49,69 @@ var starFrames = []string{"✨", "⭐", "✨"}
 // Return:
 //   - string: 当前帧的字符串表示
 func NyanFrame() string {
-	frameIdx := int(time.Now().UnixMilli()/250) % len(catFrames)
-	return nyanFrameAt(frameIdx)
+	return NyanFrameWith(NyanOptions{})
+}
+
+// NyanFrameWith 按指定心情和尾巴参数返回当前 Nyan Cat 动画帧
+// Parameters:
+//   - opts: 帧参数
+//
+// Return:
+//   - string: 当前帧的字符串表示
+func NyanFrameWith(opts NyanOptions) string {
+	if !opts.Motion.Animated() {
+		opts.Flicker = false
+		return renderNyanFrame(0, opts)
+	}
+	now := time.Now()
+	if sp := opts.Sprite; sp != nil && len(sp.Frames) > 0 {
+		return renderNyanFrame(opts.Motion.frameIndex(now, sp.period()), opts)
+	}
+	frameIdx := opts.Motion.frameIndex(now, 250) % len(catFrames)
+	return renderNyanFrame(frameIdx, opts)
 }
 
-// nyanFrameAt 根据帧索引生成 Nyan Cat 动画帧
+// nyanFrameAt 根据帧索引生成默认心情的 Nyan Cat 动画帧
 // Parameters:
 //   - frameIdx: 帧索引, 同时用于驱动尾巴颜色偏移、猫咪和星星的切换
 //
 // Return:
 //   - string: 当前帧的字符串表示
 func nyanFrameAt(frameIdx int) string {
+	return renderNyanFrame(frameIdx, NyanOptions{})
+}
+
+// renderNyanFrame 根据帧索引和帧参数生成 Nyan Cat 动画帧
+func renderNyanFrame(frameIdx int, opts NyanOptions) string {
 	// 彩虹尾巴: 使用 ANSI 256 色渲染 "█" 字符
 	// 7 色方案: Red(196), Orange(208), Yellow(226), Green(46), Cyan(51), Blue(21), Violet(93)
 	// 颜色每帧偏移一位, 产生滚动效果
 	n := len(rainbow256)
+	tailLen := opts.TailLen
+	if tailLen <= 0 || tailLen > n {
+		tailLen = n
+	}
 	offset := frameIdx % n
 	tail := make([]byte, 0, n*16) // 预分配足够空间
-	for i := range n {
+	for i := range tailLen {
+		if opts.Flicker && frameIdx%2 == 1 {
+			tail = append(tail, "\033[90m█"...)
+			continue
+		}
 		idx := (i + offset) % n
 		tail = fmt.Appendf(tail, "\033[38;5;%dm█", rainbow256[idx])
 	}
 	tail = append(tail, "\033[0m"...)
 
-	cat := catFrames[frameIdx%len(catFrames)]
-	star := starFrames[frameIdx%len(starFrames)]
+	if opts.Sprite != nil && len(opts.Sprite.Frames) > 0 {
+		return string(tail) + opts.Sprite.frameAt(frameIdx)
+	}
 
+	frames, ok := moodFrames[opts.Mood]
+	if !ok {
+		frames = catFrames
+	}
+	cat := frames[frameIdx%len(frames)]
+	star := starFrames[frameIdx%len(starFrames)]
 	return string(tail) + cat + star
 }
@@ -97,3 +97,53 @@ func TestNyanFrameAt_FrameCycle(t *testing.T) {
 		}
 	}
 }
+
+// TestRenderNyanFrame_MoodFrames 验证每种心情使用对应的猫咪帧
+func TestRenderNyanFrame_MoodFrames(t *testing.T) {
+	for mood, frames := range moodFrames {
+		for i := range len(frames) {
+			frame := renderNyanFrame(i, NyanOptions{Mood: mood})
+			if !strings.Contains(frame, frames[i]) {
+				t.Errorf("mood %s frame %d should contain %q, got %q", mood, i, frames[i], frame)
+			}
+		}
+	}
+}
+
+// TestRenderNyanFrame_UnknownMood 未知心情回退到默认猫咪帧
+func TestRenderNyanFrame_UnknownMood(t *testing.T) {
+	got := renderNyanFrame(0, NyanOptions{Mood: "grumpy"})
+	if got != nyanFrameAt(0) {
+		t.Errorf("unknown mood should fall back to default frame, got %q", got)
+	}
+}
+
+// TestRenderNyanFrame_TailLen 验证尾巴缩短, 超出范围时使用完整长度
+func TestRenderNyanFrame_TailLen(t *testing.T) {
+	tests := []struct {
+		tailLen int
+		want    int
+	}{
+		{0, len(rainbow256)},
+		{3, 3},
+		{1, 1},
+		{99, len(rainbow256)},
+	}
+	for _, tt := range tests {
+		frame := renderNyanFrame(0, NyanOptions{TailLen: tt.tailLen})
+		if got := strings.Count(frame, "█"); got != tt.want {
+			t.Errorf("TailLen=%d: tail cells = %d, want %d", tt.tailLen, got, tt.want)
+		}
+	}
+}
+
+// TestRenderNyanFrame_Flicker 闪烁时奇数帧尾巴变灰, 偶数帧保持彩虹
+func TestRenderNyanFrame_Flicker(t *testing.T) {
+	opts := NyanOptions{Flicker: true}
+	if strings.Contains(renderNyanFrame(1, opts), ansi256Color(rainbow256[0])) {
+		t.Error("odd flicker frame should not contain rainbow colors")
+	}
+	if !strings.Contains(renderNyanFrame(0, opts), ansi256Color(rainbow256[0])) {
+		t.Error("even flicker frame should keep rainbow colors")
+	}
+}
@@ -0,0 +1,133 @@
+package animation
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// BarStyle 进度条样式
+type BarStyle string
+
+// 进度条样式取值
+const (
+	BarBlocks    BarStyle = "blocks"    // █ + 八分之一块 ▏▎▍▌▋▊▉
+	BarDots      BarStyle = "dots"      // 盲文点阵 ⣿ + 逐点填充 ⣄⣤⣦⣶⣷, 空格 ⣀
+	BarThin      BarStyle = "thin"      // 细线 ━╸
+	BarSegmented BarStyle = "segmented" // 分段 ▰▱
+)
+
+// barGlyphs 进度条样式的字符集, 所有字符视觉宽度均为 1
+type barGlyphs struct {
+	full     string
+	partials []string // 按填充比例递增的部分填充字符 (不含满格)
+	empty    string
+}
+
+var barStyles = map[BarStyle]barGlyphs{
+	BarBlocks:    {full: "█", partials: []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉"}, empty: "░"},
+	BarDots:      {full: "⣿", partials: []string{"⣄", "⣤", "⣦", "⣶", "⣷"}, empty: "⣀"},
+	BarThin:      {full: "━", partials: []string{"╸"}, empty: "─"},
+	BarSegmented: {full: "▰", empty: "▱"},
+}
+
+// barMarker 标记位置 (如自动压缩阈值) 使用的字符
+const barMarker = "┊"
+
+// rainbowRGB rainbow256 对应的 24 位色值, 用于真彩色插值
+var rainbowRGB = [][3]int{
+	{255, 0, 0}, {255, 135, 0}, {255, 255, 0}, {0, 255, 0},
+	{0, 255, 255}, {0, 0, 255}, {135, 0, 255},
+}
+
+// BarOptions 进度条参数
+type BarOptions struct {
+	Width     int      // 字符宽度, <= 0 时为 10
+	Style     BarStyle // 样式, 空值或未知样式为 BarBlocks
+	TrueColor bool     // 使用 24 位真彩色渐变 (否则使用 ANSI 256 色 7 色方案)
+	Marker    float64  // 标记位置百分比, <= 0 或 >= 100 时不显示
+	Motion    Motion   // 运动参数, 动画时彩虹色沿进度条滚动
+}
+
+// ProgressBar 生成彩虹渐变进度条, 末格按样式精度显示部分填充
+// Parameters:
+//   - percent: 百分比 (0-100)
+//   - opts: 进度条参数
+//
+// Return:
+//   - string: 带 ANSI 颜色的进度条字符串
+func ProgressBar(percent float64, opts BarOptions) string {
+	return progressBarAt(percent, opts, opts.Motion.frameIndex(time.Now(), 250))
+}
+
+// progressBarAt 根据颜色偏移生成进度条
+func progressBarAt(percent float64, opts BarOptions, offset int) string {
+	width := opts.Width
+	if width <= 0 {
+		width = 10
+	}
+	glyphs, ok := barStyles[opts.Style]
+	if !ok {
+		glyphs = barStyles[BarBlocks]
+	}
+	filled := math.Max(0, math.Min(percent, 100)) / 100 * float64(width)
+	markerCell := -1
+	if opts.Marker > 0 && opts.Marker < 100 {
+		markerCell = min(int(opts.Marker/100*float64(width)), width-1)
+	}
+
+	var b strings.Builder
+	for i := range width {
+		glyph, lit := glyphs.cell(filled - float64(i))
+		switch {
+		case lit:
+			b.WriteString(barColor(i, width, offset, opts.TrueColor))
+		case i == markerCell:
+			glyph = barMarker
+			b.WriteString("\033[97m")
+		default:
+			b.WriteString("\033[90m")
+		}
+		b.WriteString(glyph)
+	}
+	b.WriteString("\033[0m")
+	return b.String()
+}
+
+// cell 根据该格的填充比例返回字符及是否有填充 (决定着色), fill <= 0 为空格, >= 1 为满格
+func (g barGlyphs) cell(fill float64) (string, bool) {
+	if fill >= 1 {
+		return g.full, true
+	}
+	levels := len(g.partials) + 1
+	idx := int(fill * float64(levels))
+	if idx <= 0 {
+		return g.empty, false
+	}
+	return g.partials[idx-1], true
+}
+
+// barColor 返回第 i 格的前景色序列, 颜色沿进度条从红到紫渐变
+func barColor(i, width, offset int, trueColor bool) string {
+	n := len(rainbow256)
+	if !trueColor {
+		colorIdx := min(i*n/width, n-1)
+		return fmt.Sprintf("\033[38;5;%dm", rainbow256[(colorIdx+offset)%n])
+	}
+
+	// 在 7 个色标间线性插值, 偏移按整色标滚动
+	pos := 0.0
+	if width > 1 {
+		pos = float64(i) / float64(width-1) * float64(n-1)
+	}
+	pos = math.Mod(pos+float64(offset), float64(n))
+	lo := int(pos)
+	hi := (lo + 1) % n
+	t := pos - float64(lo)
+	c := [3]int{}
+	for k := range c {
+		c[k] = int(math.Round(float64(rainbowRGB[lo][k])*(1-t) + float64(rainbowRGB[hi][k])*t))
+	}
+	return fmt.Sprintf("\033[38;2;%d;%d;%dm", c[0], c[1], c[2])
+}
@@ -0,0 +1,109 @@
+package animation
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// stripColor 去掉进度条中的 ANSI 序列, 只保留字符
+func stripColor(s string) string {
+	var b strings.Builder
+	inEsc := false
+	for _, r := range s {
+		switch {
+		case r == '\033':
+			inEsc = true
+		case inEsc && r == 'm':
+			inEsc = false
+		case !inEsc:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// TestProgressBar_SubCell 验证八分之一块精度: 9.9% 不再显示为空
+func TestProgressBar_SubCell(t *testing.T) {
+	tests := []struct {
+		percent float64
+		want    string
+	}{
+		{0, "░░░░░░░░░░"},
+		{1.25, "▏░░░░░░░░░"},
+		{9.9, "▉░░░░░░░░░"},
+		{10, "█░░░░░░░░░"},
+		{55, "█████▌░░░░"},
+		{100, "██████████"},
+		{150, "██████████"},
+		{-5, "░░░░░░░░░░"},
+	}
+	for _, tt := range tests {
+		got := stripColor(progressBarAt(tt.percent, BarOptions{Width: 10}, 0))
+		if got != tt.want {
+			t.Errorf("percent=%v: bar = %q, want %q", tt.percent, got, tt.want)
+		}
+	}
+}
+
+// TestProgressBar_Styles 验证各样式的满格、部分填充和空格字符
+func TestProgressBar_Styles(t *testing.T) {
+	tests := []struct {
+		style BarStyle
+		want  string
+	}{
+		{BarDots, "⣿⣦⣀⣀"},
+		{BarThin, "━╸──"},
+		{BarSegmented, "▰▱▱▱"}, // 分段样式无部分填充, 按整格截断
+		{"unknown", "█▌░░"},
+	}
+	for _, tt := range tests {
+		got := stripColor(progressBarAt(37.5, BarOptions{Width: 4, Style: tt.style}, 0))
+		if got != tt.want {
+			t.Errorf("style %q: bar = %q, want %q", tt.style, got, tt.want)
+		}
+	}
+}
+
+// TestBarStyles_DistinctEmpty 空格字符不能与部分填充或满格相同, 否则小比例填充看不出来
+func TestBarStyles_DistinctEmpty(t *testing.T) {
+	for style, g := range barStyles {
+		if slices.Contains(g.partials, g.empty) || g.full == g.empty {
+			t.Errorf("style %q: empty glyph %q duplicates a fill glyph", style, g.empty)
+		}
+	}
+}
+
+// TestProgressBar_Marker 标记显示在未填充的阈值格上, 已填充时不覆盖
+func TestProgressBar_Marker(t *testing.T) {
+	got := stripColor(progressBarAt(20, BarOptions{Width: 10, Marker: 80}, 0))
+	if got != "██░░░░░░┊░" {
+		t.Errorf("bar with marker = %q", got)
+	}
+	got = stripColor(progressBarAt(90, BarOptions{Width: 10, Marker: 80}, 0))
+	if strings.Contains(got, barMarker) {
+		t.Errorf("filled marker cell should keep fill glyph, got %q", got)
+	}
+}
+
+// TestProgressBar_TrueColor 真彩色渐变首尾为红色和紫色
+func TestProgressBar_TrueColor(t *testing.T) {
+	bar := progressBarAt(100, BarOptions{Width: 10, TrueColor: true}, 0)
+	if !strings.HasPrefix(bar, "\033[38;2;255;0;0m") {
+		t.Errorf("truecolor bar should start with red, got %q", bar[:min(20, len(bar))])
+	}
+	if !strings.Contains(bar, "\033[38;2;135;0;255m█\033[0m") {
+		t.Error("truecolor bar should end with violet")
+	}
+	if strings.Contains(bar, "38;5;") {
+		t.Error("truecolor bar should not use 256 color sequences")
+	}
+}
+
+// TestProgressBar_Offset 动画偏移使彩虹色滚动
+func TestProgressBar_Offset(t *testing.T) {
+	bar := progressBarAt(100, BarOptions{Width: 7}, 1)
+	if !strings.HasPrefix(bar, ansi256Color(rainbow256[1])) {
+		t.Errorf("offset bar should start with second rainbow color, got %q", bar[:min(20, len(bar))])
+	}
+}
@@ -0,0 +1,101 @@
+package animation
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// defaultFrameMs 精灵未指定帧时长时使用的默认值 (约 4fps, 与 Nyan Cat 一致)
+const defaultFrameMs = 250
+
+// Sprite 可自定义的动画帧集合, 可从 JSON 文件加载
+type Sprite struct {
+	Name    string   `json:"name"`
+	Frames  []string `json:"frames"`
+	Colors  []int    `json:"colors,omitempty"`   // 每帧 ANSI 256 前景色, 缺省或 -1 表示不着色
+	FrameMs int      `json:"frame_ms,omitempty"` // 每帧时长 (毫秒), 缺省 250
+	Width   int      `json:"width,omitempty"`    // 每帧视觉宽度, 加载时统一补齐
+}
+
+// builtinSprites 内置的替代精灵, 用于替换 Nyan Cat 的猫咪和星星
+var builtinSprites = map[string]Sprite{
+	"dog": {
+		Name:   "dog",
+		Frames: []string{"🐶🦴", "🐕🦴", "🐶🦴", "🐩🦴"},
+		Width:  4,
+	},
+	"rocket": {
+		Name:   "rocket",
+		Frames: []string{"🚀🌟", "🚀💫", "🚀🔥"},
+		Width:  4,
+	},
+	"pacman": {
+		Name:    "pacman",
+		Frames:  []string{"ᗧ•••", "●•••", "ᗧ ••", "● ••"},
+		Colors:  []int{226, 226, 226, 226},
+		FrameMs: 200,
+		Width:   4,
+	},
+	"spark": {
+		Name:    "spark",
+		Frames:  []string{"✻", "✼", "✽", "✺"},
+		Colors:  []int{173, 209, 216, 209},
+		FrameMs: 333,
+		Width:   1,
+	},
+}
+
+// BuiltinSprite 按名称查找内置精灵
+// Parameters:
+//   - name: 精灵名称
+//
+// Return:
+//   - Sprite: 精灵副本
+//   - bool: 是否存在
+func BuiltinSprite(name string) (Sprite, bool) {
+	sp, ok := builtinSprites[name]
+	return sp, ok
+}
+
+// BuiltinSpriteNames 返回按名称排序的内置精灵列表
+func BuiltinSpriteNames() []string {
+	names := make([]string, 0, len(builtinSprites))
+	for name := range builtinSprites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SpriteFrame 返回精灵的当前动画帧
+// Parameters:
+//   - sp: 精灵
+//   - m: 运动参数, 静止时返回首帧
+//
+// Return:
+//   - string: 当前帧, 无帧时返回空串
+func SpriteFrame(sp Sprite, m Motion) string {
+	if len(sp.Frames) == 0 {
+		return ""
+	}
+	return sp.frameAt(m.frameIndex(time.Now(), sp.period()))
+}
+
+// period 返回帧时长 (毫秒), 未指定时使用默认值
+func (sp Sprite) period() int64 {
+	if sp.FrameMs <= 0 {
+		return defaultFrameMs
+	}
+	return int64(sp.FrameMs)
+}
+
+// frameAt 根据帧索引返回着色后的帧
+func (sp Sprite) frameAt(frameIdx int) string {
+	i := frameIdx % len(sp.Frames)
+	frame := sp.Frames[i]
+	if i < len(sp.Colors) && sp.Colors[i] >= 0 {
+		return fmt.Sprintf("\033[38;5;%dm%s\033[0m", sp.Colors[i], frame)
+	}
+	return frame
+}
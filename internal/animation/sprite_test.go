@@ -0,0 +1,66 @@
+package animation
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestBuiltinSprites_Valid 验证内置精灵都有帧且名称一致
+func TestBuiltinSprites_Valid(t *testing.T) {
+	for _, name := range BuiltinSpriteNames() {
+		sp, ok := BuiltinSprite(name)
+		if !ok {
+			t.Fatalf("BuiltinSprite(%q) not found", name)
+		}
+		if sp.Name != name {
+			t.Errorf("sprite %q has Name %q", name, sp.Name)
+		}
+		if len(sp.Frames) == 0 {
+			t.Errorf("sprite %q has no frames", name)
+		}
+		if SpriteFrame(sp, Motion{}) == "" {
+			t.Errorf("SpriteFrame(%q) should not be empty", name)
+		}
+	}
+}
+
+// TestSpriteFrameAt_Colors 指定颜色的帧带 ANSI 256 色, -1 和缺省不着色
+func TestSpriteFrameAt_Colors(t *testing.T) {
+	sp := Sprite{Frames: []string{"a", "b", "c"}, Colors: []int{196, -1}}
+	if got := sp.frameAt(0); got != ansi256Color(196)+"a\033[0m" {
+		t.Errorf("frame 0 = %q, want colored", got)
+	}
+	if got := sp.frameAt(1); got != "b" {
+		t.Errorf("frame 1 = %q, want uncolored", got)
+	}
+	if got := sp.frameAt(2); got != "c" {
+		t.Errorf("frame 2 = %q, want uncolored", got)
+	}
+	if got := sp.frameAt(3); !strings.Contains(got, "a") {
+		t.Errorf("frame 3 should wrap to frame 0, got %q", got)
+	}
+}
+
+// TestSpriteFrame_Empty 无帧精灵返回空串
+func TestSpriteFrame_Empty(t *testing.T) {
+	if got := SpriteFrame(Sprite{}, Motion{}); got != "" {
+		t.Errorf("SpriteFrame(empty) = %q, want empty", got)
+	}
+}
+
+// TestRenderNyanFrame_Sprite 自定义精灵替换猫咪和星星, 保留彩虹尾巴
+func TestRenderNyanFrame_Sprite(t *testing.T) {
+	sp, _ := BuiltinSprite("rocket")
+	frame := renderNyanFrame(0, NyanOptions{Mood: MoodSweating, Sprite: &sp})
+	if !strings.Contains(frame, "🚀") {
+		t.Errorf("sprite frame should contain rocket, got %q", frame)
+	}
+	for _, cat := range moodFrames[MoodSweating] {
+		if strings.Contains(frame, cat) {
+			t.Errorf("sprite frame should not contain cat %q", cat)
+		}
+	}
+	if strings.Count(frame, "█") != len(rainbow256) {
+		t.Errorf("sprite frame should keep the rainbow tail, got %q", frame)
+	}
+}
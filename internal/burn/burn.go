@@ -0,0 +1,150 @@
+// Package burn 记录会话快照并计算消耗速率 (成本/小时、输出 token/分钟、上下文填满预估)
+//
+// 每次状态栏渲染都是独立进程, 需要将快照持久化到文件才能计算近期速率:
+//   - 每个会话保留最近 sampleWindow 内的快照, 间隔不足 minSampleGap 的渲染不追加
+//   - 超过 sessionTTL 未更新的会话会被清理
+package burn
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/safefile"
+)
+
+const samplesFileName = "nyan-samples.json"
+
+const (
+	sampleWindow = 15 * time.Minute // 速率计算窗口
+	minSampleGap = 10 * time.Second // 相邻快照最小间隔
+	minRateSpan  = time.Minute      // 窗口跨度不足时不计算速率
+	sessionTTL   = 24 * time.Hour   // 会话快照保留时长
+)
+
+// Sample 一次渲染时的会话快照
+type Sample struct {
+	At            int64   `json:"t"`    // 快照时间 (Unix 毫秒)
+	CostUSD       float64 `json:"cost"` // 会话累计成本
+	OutputTokens  int64   `json:"out"`  // 会话累计输出 token
+	ContextTokens int64   `json:"ctx"`  // 当前上下文已用 token
+}
+
+// samplesFile 快照文件结构: session_id → 快照列表 (按时间升序)
+type samplesFile struct {
+	Sessions map[string][]Sample `json:"sessions"`
+}
+
+// Rates 近期消耗速率
+type Rates struct {
+	CostPerHour     float64 // 成本/小时
+	OutputPerMinute float64 // 输出 token/分钟
+	ContextPerMin   float64 // 上下文增长 token/分钟, 压缩后从压缩点重新计算
+	HasRates        bool    // 窗口跨度是否足够计算速率
+}
+
+// Record 追加会话快照并返回该会话窗口内的快照
+// Parameters:
+//   - dir: 快照文件所在目录
+//   - sessionID: 会话 ID, 为空时不记录
+//   - s: 当前快照
+//
+// Return:
+//   - []Sample: 该会话窗口内的快照 (含当前快照)
+//   - error: 写入错误
+func Record(dir, sessionID string, s Sample) ([]Sample, error) {
+	if sessionID == "" {
+		return []Sample{s}, nil
+	}
+	path := filepath.Join(dir, samplesFileName)
+
+	var f samplesFile
+	if raw, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(raw, &f)
+	}
+	if f.Sessions == nil {
+		f.Sessions = make(map[string][]Sample)
+	}
+
+	now := time.UnixMilli(s.At)
+	for id, list := range f.Sessions {
+		if len(list) == 0 || now.Sub(time.UnixMilli(list[len(list)-1].At)) > sessionTTL {
+			delete(f.Sessions, id)
+		}
+	}
+
+	list := f.Sessions[sessionID]
+	if n := len(list); n > 0 && time.Duration(s.At-list[n-1].At)*time.Millisecond < minSampleGap {
+		// 间隔过短: 不落盘, 但用当前快照参与计算
+		return append(trimWindow(list, now), s), nil
+	}
+	list = trimWindow(append(list, s), now)
+	f.Sessions[sessionID] = list
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return list, err
+	}
+	return list, safefile.Write(path, data)
+}
+
+// trimWindow 丢弃窗口之外的快照
+func trimWindow(list []Sample, now time.Time) []Sample {
+	cutoff := now.Add(-sampleWindow).UnixMilli()
+	i := 0
+	for i < len(list) && list[i].At < cutoff {
+		i++
+	}
+	return append([]Sample(nil), list[i:]...)
+}
+
+// Compute 根据窗口内首尾快照计算速率
+// Parameters:
+//   - samples: 按时间升序的快照
+//
+// Return:
+//   - Rates: 速率, 跨度不足 minRateSpan 时 HasRates 为 false
+func Compute(samples []Sample) Rates {
+	if len(samples) < 2 {
+		return Rates{}
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	span := time.Duration(last.At-first.At) * time.Millisecond
+	if span < minRateSpan {
+		return Rates{}
+	}
+
+	r := Rates{
+		CostPerHour:     max(last.CostUSD-first.CostUSD, 0) / span.Hours(),
+		OutputPerMinute: float64(max(last.OutputTokens-first.OutputTokens, 0)) / span.Minutes(),
+		HasRates:        true,
+	}
+
+	// 上下文在压缩后会骤降, 从最后一次下降处开始计算增长
+	base := 0
+	for i := 1; i < len(samples); i++ {
+		if samples[i].ContextTokens < samples[i-1].ContextTokens {
+			base = i
+		}
+	}
+	if ctxSpan := time.Duration(last.At-samples[base].At) * time.Millisecond; ctxSpan >= minRateSpan {
+		r.ContextPerMin = float64(max(last.ContextTokens-samples[base].ContextTokens, 0)) / ctxSpan.Minutes()
+	}
+	return r
+}
+
+// TimeToFull 按上下文增长速率预估剩余空间耗尽的时间
+// Parameters:
+//   - r: 速率
+//   - remaining: 剩余 token
+//
+// Return:
+//   - time.Duration: 预估时长
+//   - bool: 上下文无增长或无剩余空间时为 false
+func TimeToFull(r Rates, remaining int64) (time.Duration, bool) {
+	if r.ContextPerMin <= 0 || remaining <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(remaining) / r.ContextPerMin * float64(time.Minute)), true
+}
@@ -0,0 +1,103 @@
+package burn
+
+import (
+	"testing"
+	"time"
+)
+
+var testStart = time.Date(2026, 2, 26, 10, 0, 0, 0, time.UTC)
+
+// sampleAt 构造相对 testStart 的快照
+func sampleAt(d time.Duration, cost float64, out, ctx int64) Sample {
+	return Sample{At: testStart.Add(d).UnixMilli(), CostUSD: cost, OutputTokens: out, ContextTokens: ctx}
+}
+
+func TestCompute_NotEnoughSamples(t *testing.T) {
+	if r := Compute(nil); r.HasRates {
+		t.Error("Compute(nil) should not have rates")
+	}
+	short := []Sample{sampleAt(0, 0, 0, 0), sampleAt(30*time.Second, 1, 100, 100)}
+	if r := Compute(short); r.HasRates {
+		t.Error("span below minRateSpan should not have rates")
+	}
+}
+
+func TestCompute_Rates(t *testing.T) {
+	samples := []Sample{
+		sampleAt(0, 1.0, 1000, 20000),
+		sampleAt(5*time.Minute, 1.5, 3000, 40000),
+		sampleAt(10*time.Minute, 2.0, 6000, 60000),
+	}
+	r := Compute(samples)
+	if !r.HasRates {
+		t.Fatal("Compute() should have rates")
+	}
+	if r.CostPerHour != 6.0 {
+		t.Errorf("CostPerHour = %f, want 6.0", r.CostPerHour)
+	}
+	if r.OutputPerMinute != 500 {
+		t.Errorf("OutputPerMinute = %f, want 500", r.OutputPerMinute)
+	}
+	if r.ContextPerMin != 4000 {
+		t.Errorf("ContextPerMin = %f, want 4000", r.ContextPerMin)
+	}
+}
+
+// TestCompute_AfterCompaction 上下文压缩后从压缩点重新计算增长
+func TestCompute_AfterCompaction(t *testing.T) {
+	samples := []Sample{
+		sampleAt(0, 1, 0, 150000),
+		sampleAt(4*time.Minute, 1, 0, 30000), // 压缩
+		sampleAt(6*time.Minute, 1, 0, 40000),
+	}
+	if r := Compute(samples); r.ContextPerMin != 5000 {
+		t.Errorf("ContextPerMin = %f, want 5000", r.ContextPerMin)
+	}
+}
+
+func TestTimeToFull(t *testing.T) {
+	d, ok := TimeToFull(Rates{ContextPerMin: 4000}, 100000)
+	if !ok || d != 25*time.Minute {
+		t.Errorf("TimeToFull() = %v %v, want 25m true", d, ok)
+	}
+	if _, ok := TimeToFull(Rates{}, 100000); ok {
+		t.Error("TimeToFull without growth should be false")
+	}
+}
+
+// TestRecord_WindowAndThrottle 验证快照持久化、节流和窗口裁剪
+func TestRecord_WindowAndThrottle(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Record(dir, "s1", sampleAt(0, 0, 0, 0)); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+	// 间隔过短: 返回值包含当前快照, 但不落盘
+	got, _ := Record(dir, "s1", sampleAt(5*time.Second, 0, 0, 0))
+	if len(got) != 2 {
+		t.Errorf("throttled Record() returned %d samples, want 2", len(got))
+	}
+	got, _ = Record(dir, "s1", sampleAt(time.Minute, 0, 0, 0))
+	if len(got) != 2 {
+		t.Errorf("Record() returned %d samples, want 2 (throttled one not persisted)", len(got))
+	}
+	// 超出窗口的快照被丢弃
+	got, _ = Record(dir, "s1", sampleAt(20*time.Minute, 0, 0, 0))
+	if len(got) != 1 {
+		t.Errorf("Record() after window returned %d samples, want 1", len(got))
+	}
+}
+
+// TestRecord_SessionsIsolated 不同会话的快照互不影响, 过期会话被清理
+func TestRecord_SessionsIsolated(t *testing.T) {
+	dir := t.TempDir()
+	_, _ = Record(dir, "old", sampleAt(0, 0, 0, 0))
+	_, _ = Record(dir, "a", sampleAt(25*time.Hour, 0, 0, 0))
+	got, _ := Record(dir, "a", sampleAt(25*time.Hour+time.Minute, 0, 0, 0))
+	if len(got) != 2 {
+		t.Errorf("session a has %d samples, want 2", len(got))
+	}
+	got, _ = Record(dir, "old", sampleAt(25*time.Hour+2*time.Minute, 0, 0, 0))
+	if len(got) != 1 {
+		t.Errorf("expired session should restart with 1 sample, got %d", len(got))
+	}
+}
@@ -5,15 +5,132 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/formatter"
+	"github.com/nyan-statusline-cc/internal/pricing"
 )
 
 const configFileName = "nyan-config.json"
 
 // Config 状态栏显示配置
 type Config struct {
-	Line2Enabled bool            `json:"line2_enabled"`
-	Line1        map[string]bool `json:"line1"`
-	Line2        map[string]bool `json:"line2"`
+	Line2Enabled bool                     `json:"line2_enabled"`
+	Line1        map[string]bool          `json:"line1"`
+	Line2        map[string]bool          `json:"line2"`
+	Notify       NotifyConfig             `json:"notify"`
+	Mood         MoodConfig               `json:"mood"`
+	Sprites      map[string]string        `json:"sprites"` // 动画槽位 (nyan/heartbeat) → 精灵名称
+	Motion       MotionConfig             `json:"motion"`
+	Progress     ProgressConfig           `json:"progress"`
+	Context      ContextConfig            `json:"context"`
+	Budget       BudgetConfig             `json:"budget"`
+	Block        BlockConfig              `json:"block"`
+	Prices       map[string]pricing.Price `json:"prices"` // model.id 子串 → 每百万 token 价格, 覆盖内置价格表
+	Format       FormatConfig             `json:"format"`
+	Sparkline    SparklineConfig          `json:"sparkline"`
+	Achievements AchievementConfig        `json:"achievements"`
+	Streak       StreakConfig             `json:"streak"`
+	DataDir      string                   `json:"data_dir"`       // Claude Code 数据目录 (stats-cache.json, projects/), 为空时自动探测
+	Timezone     string                   `json:"timezone"`       // 统计日期使用的 IANA 时区 (如 "Asia/Shanghai"), 为空时使用系统时区
+	DayStartHour int                      `json:"day_start_hour"` // 统计日起始小时 (0-23), 如 4 表示凌晨 4 点前仍算前一天
+}
+
+// FormatConfig 数字、货币与时长显示格式; 预算与价格仍以 USD 配置, 显示时按汇率换算
+type FormatConfig struct {
+	CurrencySymbol string  `json:"currency_symbol"` // 货币符号, 如 "¥"
+	ExchangeRate   float64 `json:"exchange_rate"`   // USD → 显示货币汇率
+	ThousandsSep   string  `json:"thousands_sep"`   // 千分位分隔符, 为空时不分组
+	DurationStyle  string  `json:"duration_style"`  // compact (2m30s) / verbose (2分30秒)
+}
+
+// AchievementConfig 成就徽章展示配置
+type AchievementConfig struct {
+	Display   string `json:"display"`    // newest (最近解锁) / rarest (最稀有) / rotate (轮播)
+	RotateSec int    `json:"rotate_sec"` // 轮播间隔 (秒)
+	Progress  string `json:"progress"`   // 升级进度: nearest (最接近升级的系列) / all (全部系列)
+}
+
+// StreakConfig 连续活跃规则
+type StreakConfig struct {
+	WorkdaysOnly    bool `json:"workdays_only"`     // 只要求工作日活跃, 周末无活动不中断
+	FreezesPerMonth int  `json:"freezes_per_month"` // 每月可冻结 (缺席不中断) 的天数
+	MinMessages     int  `json:"min_messages"`      // 单日消息数达到该值才算活跃
+}
+
+// SparklineConfig 消息趋势图配置
+type SparklineConfig struct {
+	Days int `json:"days"` // 显示最近天数 (1-30), 如 7/14/30
+}
+
+// Locale 转换为格式化器使用的显示格式
+func (f FormatConfig) Locale() formatter.Locale {
+	return formatter.Locale{
+		CurrencySymbol: f.CurrencySymbol,
+		ExchangeRate:   f.ExchangeRate,
+		ThousandsSep:   f.ThousandsSep,
+		DurationStyle:  f.DurationStyle,
+	}
+}
+
+// Location 返回统计日期使用的时区, 未配置或无法识别时使用系统时区
+func (c *Config) Location() *time.Location {
+	if c.Timezone != "" {
+		if loc, err := time.LoadLocation(c.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// BlockConfig 订阅计划滚动用量窗口配置
+type BlockConfig struct {
+	Hours      float64 `json:"hours"`       // 窗口长度 (小时)
+	TokenLimit int64   `json:"token_limit"` // 窗口 token 上限, 0 表示不显示进度条
+}
+
+// BudgetConfig 花费预算 (USD), 0 表示不设预算
+type BudgetConfig struct {
+	Daily   float64 `json:"daily"`
+	Weekly  float64 `json:"weekly"`
+	Monthly float64 `json:"monthly"`
+}
+
+// ProgressConfig 上下文进度条配置
+type ProgressConfig struct {
+	Width         int    `json:"width"`          // 字符宽度
+	Style         string `json:"style"`          // blocks/dots/thin/segmented
+	TrueColor     bool   `json:"truecolor"`      // 24 位真彩色渐变
+	CompactMarker bool   `json:"compact_marker"` // 在自动压缩阈值处显示标记
+}
+
+// ContextConfig 上下文窗口配置
+type ContextConfig struct {
+	// Modes 上下文段显示模式 (按顺序): percent/tokens/remaining/cache/compact
+	Modes              []string `json:"modes"`
+	AutoCompactPercent float64  `json:"auto_compact_percent"` // 自动压缩触发阈值 (百分比)
+}
+
+// MotionConfig 动画运动配置
+type MotionConfig struct {
+	Level string `json:"level"` // full/reduced/off
+	// Speed 各动画速度倍率 (nyan/heartbeat/randomStatus/progress/newRecord), 0 表示静止, 未配置按 1 倍速
+	Speed map[string]float64 `json:"speed"`
+}
+
+// NotifyConfig 处理完成通知配置, 每种通知方式需单独开启
+type NotifyConfig struct {
+	MinDurationSec int  `json:"min_duration_sec"` // 处理时长达到该秒数才通知
+	Bell           bool `json:"bell"`             // 终端响铃 (BEL)
+	OSC9           bool `json:"osc9"`             // OSC 9 桌面通知 (iTerm2/WezTerm 等)
+	OSC777         bool `json:"osc777"`           // OSC 777 桌面通知 (rxvt/foot/Ghostty 等)
+}
+
+// MoodConfig Nyan Cat 心情规则配置
+type MoodConfig struct {
+	Enabled        bool    `json:"enabled"`
+	SleepyAfterMin int     `json:"sleepy_after_min"` // 处理完成后空闲超过该分钟数显示 😴
+	SweatPercent   float64 `json:"sweat_percent"`    // 上下文使用率达到该百分比显示 😰 并缩短尾巴
 }
 
 // Line1 字段 key 列表 (有序)
@@ -26,6 +143,10 @@ var Line1Fields = []FieldDef{
 	{"changes", "+/- 代码变更"},
 	{"duration", "⏱️ 会话时长"},
 	{"tokens", "📥📤 Token"},
+	{"burnRate", "🔥 成本/小时"},
+	{"outputRate", "✍️ 输出速率"},
+	{"contextEta", "🪫 上下文填满预估"},
+	{"block", "🕔 5 小时用量窗口"},
 	{"nyan", "🐱 Nyan Cat"},
 	{"heartbeat", "💗 心跳动画"},
 }
@@ -34,12 +155,21 @@ var Line1Fields = []FieldDef{
 var Line2Fields = []FieldDef{
 	{"codingDays", "📅 使用天数"},
 	{"activeDays", "🔥 活跃天数"},
+	{"recentActive", "🗓️ 近 7/30 天活跃"},
 	{"streak", "⚡ 连续活跃"},
 	{"sessions", "💬 会话数"},
 	{"messages", "🗣️ 消息数"},
 	{"todayMessages", "📈 今日统计"},
+	{"sparkline", "📊 消息趋势"},
+	{"records", "🏆 历史纪录"},
+	{"newRecord", "🏅 新纪录提示"},
+	{"spend", "💸 花费账本"},
+	{"project", "📁 本项目统计"},
+	{"modelMix", "🧠 今日模型占比"},
 	{"peakHour", "🕐 高峰时段"},
+	{"heatmap", "🌡️ 时段热力图"},
 	{"achievement", "🏆 成就徽章"},
+	{"achievementProgress", "🎯 成就进度"},
 	{"randomStatus", "🎲 随机状态"},
 }
 
@@ -55,6 +185,18 @@ func Default() *Config {
 		Line2Enabled: true,
 		Line1:        make(map[string]bool),
 		Line2:        make(map[string]bool),
+		Notify:       NotifyConfig{MinDurationSec: 30},
+		Mood:         MoodConfig{Enabled: true, SleepyAfterMin: 30, SweatPercent: 85},
+		Motion: MotionConfig{
+			Level: "full",
+			Speed: map[string]float64{"progress": 0}, // 进度条默认静止
+		},
+		Progress:     ProgressConfig{Width: 10, Style: "blocks"},
+		Context:      ContextConfig{Modes: []string{"percent"}, AutoCompactPercent: 80},
+		Block:        BlockConfig{Hours: 5},
+		Sparkline:    SparklineConfig{Days: 7},
+		Achievements: AchievementConfig{Display: "newest", RotateSec: 10, Progress: "nearest"},
+		Format:       FormatConfig{CurrencySymbol: "$", ExchangeRate: 1, DurationStyle: "compact"},
 	}
 	for _, f := range Line1Fields {
 		c.Line1[f.Key] = true
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestDefault_AllEnabled(t *testing.T) {
@@ -77,3 +78,18 @@ func TestIsLine1Enabled_UnknownKey(t *testing.T) {
 		t.Error("Unknown key should default to enabled")
 	}
 }
+
+func TestLocation(t *testing.T) {
+	cfg := Default()
+	if cfg.Location() != time.Local {
+		t.Error("empty timezone should use time.Local")
+	}
+	cfg.Timezone = "Asia/Shanghai"
+	if got := cfg.Location().String(); got != "Asia/Shanghai" {
+		t.Errorf("Location() = %s, want Asia/Shanghai", got)
+	}
+	cfg.Timezone = "Mars/Olympus"
+	if cfg.Location() != time.Local {
+		t.Error("unknown timezone should fall back to time.Local")
+	}
+}
@@ -1,63 +1,203 @@
 // Package formatter 负责格式化状态栏中的各类数据显示
 package formatter
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
-// FormatCost 格式化费用显示
+// 时长显示风格
+const (
+	DurationCompact = "compact" // 2m30s / 1d4h
+	DurationVerbose = "verbose" // 2分30秒 / 1天4小时
+)
+
+// Locale 数字、货币与时长的显示格式
+type Locale struct {
+	CurrencySymbol string  // 货币符号, 如 "$" / "¥"
+	ExchangeRate   float64 // USD → 显示货币汇率, <= 0 按 1 处理
+	ThousandsSep   string  // 千分位分隔符, 为空时不分组
+	DurationStyle  string  // compact/verbose
+}
+
+// DefaultLocale 返回默认格式: USD、无千分位、紧凑时长
+func DefaultLocale() Locale {
+	return Locale{CurrencySymbol: "$", ExchangeRate: 1, DurationStyle: DurationCompact}
+}
+
+// current 包级函数使用的格式, 由 SetLocale 在启动时设置
+var current = DefaultLocale()
+
+// SetLocale 设置包级格式化函数使用的格式, 空字段回退到默认值
+// Parameters:
+//   - l: 显示格式
+func SetLocale(l Locale) {
+	def := DefaultLocale()
+	if l.CurrencySymbol == "" {
+		l.CurrencySymbol = def.CurrencySymbol
+	}
+	if l.ExchangeRate <= 0 {
+		l.ExchangeRate = def.ExchangeRate
+	}
+	if l.DurationStyle == "" {
+		l.DurationStyle = def.DurationStyle
+	}
+	current = l
+}
+
+// FormatCost 按当前格式格式化费用显示
 // Parameters:
 //   - cost: 费用金额 (USD)
 //
 // Return:
 //   - string: 格式化后的费用字符串
 func FormatCost(cost float64) string {
+	return current.FormatCost(cost)
+}
+
+// FormatDuration 按当前格式格式化会话时长
+// Parameters:
+//   - ms: 毫秒数
+//
+// Return:
+//   - string: 可读的时长字符串 (如 "2m30s")
+func FormatDuration(ms int64) string {
+	return current.FormatDuration(ms)
+}
+
+// FormatTokens 按当前格式格式化 token 数量
+// Parameters:
+//   - tokens: token 数量
+//
+// Return:
+//   - string: 简化后的数量字符串 (如 "50k")
+func FormatTokens(tokens int64) string {
+	return current.FormatTokens(tokens)
+}
+
+// FormatCount 按当前格式格式化计数 (消息数、会话数等)
+// Parameters:
+//   - n: 计数
+//
+// Return:
+//   - string: 带千分位的计数字符串 (如 "12,345")
+func FormatCount(n int64) string {
+	return current.FormatCount(n)
+}
+
+// FormatCost 格式化费用显示, 金额按汇率换算为显示货币
+// Parameters:
+//   - cost: 费用金额 (USD)
+//
+// Return:
+//   - string: 格式化后的费用字符串
+func (l Locale) FormatCost(cost float64) string {
 	if cost <= 0 {
-		return "$0.0000"
+		return l.CurrencySymbol + "0.0000"
+	}
+	if l.ExchangeRate > 0 {
+		cost *= l.ExchangeRate
 	}
 	if cost < 0.01 {
-		return fmt.Sprintf("$%.4f", cost)
+		return fmt.Sprintf("%s%.4f", l.CurrencySymbol, cost)
 	} else if cost < 1 {
-		return fmt.Sprintf("$%.3f", cost)
+		return fmt.Sprintf("%s%.3f", l.CurrencySymbol, cost)
 	}
-	return fmt.Sprintf("$%.2f", cost)
+	s := strconv.FormatFloat(cost, 'f', 2, 64)
+	intPart, frac, _ := strings.Cut(s, ".")
+	return l.CurrencySymbol + group(intPart, l.ThousandsSep) + "." + frac
 }
 
-// FormatDuration 格式化会话时长
+// FormatDuration 格式化时长, 超过一天时以天为最大单位
 // Parameters:
 //   - ms: 毫秒数
 //
 // Return:
-//   - string: 可读的时长字符串 (如 "2m30s")
-func FormatDuration(ms int64) string {
+//   - string: 可读的时长字符串 (如 "2m30s", verbose 风格为 "2分30秒")
+func (l Locale) FormatDuration(ms int64) string {
+	units := [3]string{"d", "h", "m"}
+	second := "s"
+	if l.DurationStyle == DurationVerbose {
+		units = [3]string{"天", "小时", "分"}
+		second = "秒"
+	}
 	if ms <= 0 {
-		return "0s"
+		return "0" + second
 	}
 	seconds := ms / 1000
-	if seconds < 60 {
-		return fmt.Sprintf("%ds", seconds)
-	} else if seconds < 3600 {
-		minutes := seconds / 60
-		secs := seconds % 60
-		return fmt.Sprintf("%dm%ds", minutes, secs)
+	switch {
+	case seconds < 60:
+		return fmt.Sprintf("%d%s", seconds, second)
+	case seconds < 3600:
+		return fmt.Sprintf("%d%s%d%s", seconds/60, units[2], seconds%60, second)
+	case seconds < 86400:
+		return fmt.Sprintf("%d%s%d%s", seconds/3600, units[1], (seconds%3600)/60, units[2])
 	}
-	hours := seconds / 3600
-	minutes := (seconds % 3600) / 60
-	return fmt.Sprintf("%dh%dm", hours, minutes)
+	return fmt.Sprintf("%d%s%d%s", seconds/86400, units[0], (seconds%86400)/3600, units[1])
 }
 
-// FormatTokens 格式化 token 数量
+// FormatTokens 格式化 token 数量, 依次使用 k/M/B 后缀
 // Parameters:
 //   - tokens: token 数量
 //
 // Return:
-//   - string: 简化后的数量字符串 (如 "50k")
-func FormatTokens(tokens int64) string {
+//   - string: 简化后的数量字符串 (如 "50k", "1.2M")
+func (l Locale) FormatTokens(tokens int64) string {
 	if tokens <= 0 {
 		return "0"
 	}
 	if tokens < 1000 {
-		return fmt.Sprintf("%d", tokens)
-	} else if tokens < 10000 {
-		return fmt.Sprintf("%.1fk", float64(tokens)/1000)
+		return strconv.FormatInt(tokens, 10)
+	}
+	scaled := []struct {
+		div    int64
+		suffix string
+	}{
+		{1_000_000_000, "B"},
+		{1_000_000, "M"},
+		{1_000, "k"},
+	}
+	for _, s := range scaled {
+		if tokens < s.div {
+			continue
+		}
+		if tokens < 10*s.div {
+			return fmt.Sprintf("%.1f%s", float64(tokens)/float64(s.div), s.suffix)
+		}
+		return group(strconv.FormatInt(tokens/s.div, 10), l.ThousandsSep) + s.suffix
+	}
+	return strconv.FormatInt(tokens, 10)
+}
+
+// FormatCount 格式化计数, 按千分位分组
+// Parameters:
+//   - n: 计数
+//
+// Return:
+//   - string: 计数字符串
+func (l Locale) FormatCount(n int64) string {
+	if n < 0 {
+		return "-" + group(strconv.FormatInt(-n, 10), l.ThousandsSep)
+	}
+	return group(strconv.FormatInt(n, 10), l.ThousandsSep)
+}
+
+// group 为十进制整数字符串插入千分位分隔符
+func group(digits, sep string) string {
+	if sep == "" || len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
 	}
-	return fmt.Sprintf("%dk", tokens/1000)
+	return b.String()
 }
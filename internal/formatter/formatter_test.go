@@ -47,8 +47,9 @@ func TestFormatDuration(t *testing.T) {
 		{"just_under_hour", 3599000, "59m59s"},
 		{"one_hour", 3600000, "1h0m"},
 		{"hours_and_minutes", 5430000, "1h30m"},
-		{"large", 86400000, "24h0m"},
-		{"very_large", 360000000, "100h0m"},
+		{"just_under_day", 86399000, "23h59m"},
+		{"one_day", 86400000, "1d0h"},
+		{"very_large", 360000000, "4d4h"},
 	}
 
 	for _, tc := range cases {
@@ -76,7 +77,12 @@ func TestFormatTokens(t *testing.T) {
 		{"near_10k", 9999, "10.0k"},
 		{"ten_k", 10000, "10k"},
 		{"large", 150000, "150k"},
-		{"million", 1000000, "1000k"},
+		{"near_million", 999999, "999k"},
+		{"million", 1000000, "1.0M"},
+		{"mid_m", 1234000, "1.2M"},
+		{"ten_m", 12345678, "12M"},
+		{"billion", 1500000000, "1.5B"},
+		{"large_b", 12000000000, "12B"},
 	}
 
 	for _, tc := range cases {
@@ -88,3 +94,88 @@ func TestFormatTokens(t *testing.T) {
 		})
 	}
 }
+
+func TestLocaleFormatCost(t *testing.T) {
+	l := Locale{CurrencySymbol: "¥", ExchangeRate: 7.2, ThousandsSep: ","}
+	cases := []struct {
+		name string
+		cost float64
+		want string
+	}{
+		{"zero", 0, "¥0.0000"},
+		{"tiny", 0.001, "¥0.0072"},
+		{"small", 0.1, "¥0.720"},
+		{"dollars", 2.5, "¥18.00"},
+		{"thousands", 12345.678, "¥88,888.88"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := l.FormatCost(tc.cost); got != tc.want {
+				t.Errorf("FormatCost(%f) = %q, want %q", tc.cost, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLocaleFormatDurationVerbose(t *testing.T) {
+	l := Locale{DurationStyle: DurationVerbose}
+	cases := []struct {
+		ms   int64
+		want string
+	}{
+		{0, "0秒"},
+		{45000, "45秒"},
+		{150000, "2分30秒"},
+		{5430000, "1小时30分"},
+		{360000000, "4天4小时"},
+	}
+
+	for _, tc := range cases {
+		if got := l.FormatDuration(tc.ms); got != tc.want {
+			t.Errorf("FormatDuration(%d) = %q, want %q", tc.ms, got, tc.want)
+		}
+	}
+}
+
+func TestLocaleFormatCount(t *testing.T) {
+	l := Locale{ThousandsSep: ","}
+	cases := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{1234567, "1,234,567"},
+		{-12345, "-12,345"},
+	}
+
+	for _, tc := range cases {
+		if got := l.FormatCount(tc.n); got != tc.want {
+			t.Errorf("FormatCount(%d) = %q, want %q", tc.n, got, tc.want)
+		}
+	}
+	if got := DefaultLocale().FormatCount(1234567); got != "1234567" {
+		t.Errorf("default FormatCount = %q, want no separators", got)
+	}
+}
+
+func TestLocaleFormatTokensGrouping(t *testing.T) {
+	l := Locale{ThousandsSep: ","}
+	if got := l.FormatTokens(1234000000000); got != "1,234B" {
+		t.Errorf("FormatTokens = %q, want %q", got, "1,234B")
+	}
+}
+
+func TestSetLocaleDefaults(t *testing.T) {
+	defer SetLocale(DefaultLocale())
+
+	SetLocale(Locale{ThousandsSep: ","})
+	if got := FormatCost(1234.5); got != "$1,234.50" {
+		t.Errorf("FormatCost = %q, want %q", got, "$1,234.50")
+	}
+	if got := FormatDuration(60000); got != "1m0s" {
+		t.Errorf("FormatDuration = %q, want compact style", got)
+	}
+}
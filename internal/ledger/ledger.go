@@ -0,0 +1,158 @@
+// Package ledger 维护跨会话的花费账本, 按日汇总并计算今日/本周/本月花费
+//
+// Claude Code 只提供当前会话的累计成本 (total_cost_usd), 账本记录每个 session_id
+// 上次看到的成本, 每次渲染把增量计入当天, 跨天的会话也能正确拆分到各自日期.
+package ledger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/safefile"
+	"github.com/nyan-statusline-cc/internal/stats"
+)
+
+const ledgerFileName = "nyan-ledger.json"
+
+// 保留时长: 会话 35 天未更新即清理, 每日汇总保留约一年
+const (
+	sessionTTL = 35 * 24 * time.Hour
+	dayTTL     = 400 * 24 * time.Hour
+)
+
+const dateLayout = "2006-01-02"
+
+// sessionEntry 会话上次看到的累计成本
+type sessionEntry struct {
+	CostUSD  float64 `json:"cost"`
+	LastSeen int64   `json:"seen"` // Unix 毫秒
+}
+
+// Ledger 花费账本
+type Ledger struct {
+	Sessions map[string]sessionEntry `json:"sessions"`
+	Days     map[string]float64      `json:"days"` // 日期 (YYYY-MM-DD) → 当日花费 USD
+}
+
+// Totals 今日/本周/本月花费汇总
+type Totals struct {
+	Today float64
+	Week  float64 // 本周 (周一起)
+	Month float64
+}
+
+// Load 读取账本, 文件不存在时返回空账本
+// Parameters:
+//   - dir: 账本文件所在目录
+//
+// Return:
+//   - *Ledger: 账本
+//   - error: 读取或解析错误 (此时不应覆盖账本文件)
+func Load(dir string) (*Ledger, error) {
+	l := &Ledger{}
+	raw, err := os.ReadFile(filepath.Join(dir, ledgerFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(raw, l); err != nil {
+			return nil, err
+		}
+	}
+	if l.Sessions == nil {
+		l.Sessions = make(map[string]sessionEntry)
+	}
+	if l.Days == nil {
+		l.Days = make(map[string]float64)
+	}
+	return l, nil
+}
+
+// Update 记录会话最新累计成本, 将增量计入 now 所在的统计日并保存
+// 多个会话同时渲染时通过文件锁串行读-改-写, 并原子替换账本文件
+// Parameters:
+//   - dir: 账本文件所在目录
+//   - sessionID: 会话 ID, 为空时只读取账本
+//   - costUSD: 会话累计成本
+//   - now: 当前时间, 其时区与统计日起始小时决定增量计入的日期
+//
+// Return:
+//   - *Ledger: 更新后的账本, 账本无法读取时为 nil
+//   - error: 加锁、读取或写入错误
+func Update(dir, sessionID string, costUSD float64, now time.Time) (*Ledger, error) {
+	path := filepath.Join(dir, ledgerFileName)
+	unlock, err := safefile.Lock(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	l, err := Load(dir)
+	if err != nil || sessionID == "" || costUSD <= 0 {
+		return l, err
+	}
+
+	prev, seen := l.Sessions[sessionID]
+	if seen && costUSD == prev.CostUSD && now.Sub(time.UnixMilli(prev.LastSeen)) < time.Hour {
+		return l, nil // 无新增花费, 避免每次渲染都写文件
+	}
+	if delta := costUSD - prev.CostUSD; delta > 0 {
+		l.Days[stats.Today(now).Format(dateLayout)] += delta
+	}
+	l.Sessions[sessionID] = sessionEntry{CostUSD: costUSD, LastSeen: now.UnixMilli()}
+	l.prune(now)
+
+	data, err := json.Marshal(l)
+	if err != nil {
+		return l, err
+	}
+	return l, safefile.Write(path, data)
+}
+
+// prune 清理过期会话和日期
+func (l *Ledger) prune(now time.Time) {
+	for id, s := range l.Sessions {
+		if now.Sub(time.UnixMilli(s.LastSeen)) > sessionTTL {
+			delete(l.Sessions, id)
+		}
+	}
+	cutoff := stats.Today(now).Add(-dayTTL).Format(dateLayout)
+	for day := range l.Days {
+		if day < cutoff {
+			delete(l.Days, day)
+		}
+	}
+}
+
+// Totals 计算 now 所在统计日及其所在周 (周一起)、月的花费
+// Parameters:
+//   - now: 当前时间, 其时区与统计日起始小时决定 "今天"
+//
+// Return:
+//   - Totals: 花费汇总
+func (l *Ledger) Totals(now time.Time) Totals {
+	date := stats.Today(now)
+	today := date.Format(dateLayout)
+	weekday := (int(date.Weekday()) + 6) % 7 // 周一为 0
+	weekStart := date.AddDate(0, 0, -weekday).Format(dateLayout)
+	monthStart := date.Format("2006-01") + "-01"
+
+	var t Totals
+	for day, cost := range l.Days {
+		if day > today {
+			continue
+		}
+		if day == today {
+			t.Today += cost
+		}
+		if day >= weekStart {
+			t.Week += cost
+		}
+		if day >= monthStart {
+			t.Month += cost
+		}
+	}
+	return t
+}
@@ -0,0 +1,151 @@
+package ledger
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/stats"
+)
+
+// 固定测试时间: 2026-02-26 (周四)
+var testNow = time.Date(2026, 2, 26, 10, 0, 0, 0, time.UTC)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestLoad_NoFile(t *testing.T) {
+	l, err := Load(t.TempDir())
+	if err != nil || len(l.Sessions) != 0 || len(l.Days) != 0 {
+		t.Errorf("Load() without file = %+v, want empty ledger", l)
+	}
+}
+
+// TestUpdate_Increments 同一会话只把增量计入当天
+func TestUpdate_Increments(t *testing.T) {
+	dir := t.TempDir()
+	_, _ = Update(dir, "s1", 1.0, testNow)
+	_, _ = Update(dir, "s1", 2.5, testNow.Add(time.Minute))
+	l, err := Update(dir, "s2", 4.0, testNow.Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if got := l.Days["2026-02-26"]; !almostEqual(got, 6.5) {
+		t.Errorf("today = %f, want 6.5", got)
+	}
+	if l, _ := Load(dir); !almostEqual(l.Days["2026-02-26"], 6.5) {
+		t.Errorf("persisted today = %f, want 6.5", l.Days["2026-02-26"])
+	}
+}
+
+// TestUpdate_SplitsAcrossDays 跨天会话的花费拆分到各自日期
+func TestUpdate_SplitsAcrossDays(t *testing.T) {
+	dir := t.TempDir()
+	_, _ = Update(dir, "s1", 3.0, testNow.Add(-24*time.Hour))
+	l, _ := Update(dir, "s1", 5.0, testNow)
+	if got := l.Days["2026-02-25"]; !almostEqual(got, 3.0) {
+		t.Errorf("yesterday = %f, want 3.0", got)
+	}
+	if got := l.Days["2026-02-26"]; !almostEqual(got, 2.0) {
+		t.Errorf("today = %f, want 2.0", got)
+	}
+}
+
+// TestUpdate_IgnoresEmpty 无 session_id 或成本为 0 时不记录
+func TestUpdate_IgnoresEmpty(t *testing.T) {
+	dir := t.TempDir()
+	_, _ = Update(dir, "", 3.0, testNow)
+	_, _ = Update(dir, "s1", 0, testNow)
+	if l, _ := Load(dir); len(l.Days) != 0 {
+		t.Errorf("Days = %v, want empty", l.Days)
+	}
+}
+
+// TestUpdate_Corrupt 账本无法解析时返回错误且不覆盖原文件
+func TestUpdate_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ledgerFileName)
+	if err := os.WriteFile(path, []byte(`{"days":{"2026-02-25":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if l, err := Update(dir, "s1", 3.0, testNow); err == nil || l != nil {
+		t.Errorf("Update() = %+v, %v; want error for corrupt ledger", l, err)
+	}
+	if raw, _ := os.ReadFile(path); string(raw) != `{"days":{"2026-02-25":` {
+		t.Errorf("ledger rewritten to %q", raw)
+	}
+}
+
+// TestUpdate_Concurrent 多个会话同时记账不丢失增量
+func TestUpdate_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = Update(dir, fmt.Sprintf("s%d", i), 1.0, testNow)
+		}()
+	}
+	wg.Wait()
+	if l, _ := Load(dir); !almostEqual(l.Days["2026-02-26"], 10) {
+		t.Errorf("today = %f, want 10", l.Days["2026-02-26"])
+	}
+}
+
+// TestUpdate_PrunesOld 过期会话和日期被清理
+func TestUpdate_PrunesOld(t *testing.T) {
+	dir := t.TempDir()
+	_, _ = Update(dir, "old", 1.0, testNow.AddDate(-2, 0, 0))
+	l, _ := Update(dir, "new", 1.0, testNow)
+	if _, ok := l.Sessions["old"]; ok {
+		t.Error("expired session should be pruned")
+	}
+	if len(l.Days) != 1 {
+		t.Errorf("Days = %v, want only today", l.Days)
+	}
+}
+
+// TestTotals 验证今日/本周/本月汇总边界
+func TestTotals(t *testing.T) {
+	l := &Ledger{Days: map[string]float64{
+		"2026-01-31": 100, // 上月
+		"2026-02-01": 1,   // 本月, 上周
+		"2026-02-22": 2,   // 上周日
+		"2026-02-23": 4,   // 本周一
+		"2026-02-26": 8,   // 今天
+		"2026-02-27": 16,  // 未来 (时钟回拨), 忽略
+	}}
+	got := l.Totals(testNow)
+	want := Totals{Today: 8, Week: 12, Month: 15}
+	if got != want {
+		t.Errorf("Totals() = %+v, want %+v", got, want)
+	}
+}
+
+// TestDayStart 花费按时区与统计日起始小时计入日期, 汇总使用同一个 "今天"
+func TestDayStart(t *testing.T) {
+	stats.SetDayStart(4)
+	t.Cleanup(func() { stats.SetDayStart(0) })
+
+	dir := t.TempDir()
+	// 上海时间 2026-03-02 (周一) 03:00, 起始小时 4 点前仍算 3-01 (周日)
+	now := time.Date(2026, 3, 1, 19, 0, 0, 0, time.UTC).In(time.FixedZone("CST", 8*3600))
+	l, _ := Update(dir, "s1", 2.0, now)
+	if got := l.Days["2026-03-01"]; !almostEqual(got, 2.0) {
+		t.Errorf("Days = %v, want 2.0 on 2026-03-01", l.Days)
+	}
+
+	l.Days["2026-02-23"] = 4 // 上周一
+	l.Days["2026-02-28"] = 8 // 上月
+	got := l.Totals(now)
+	want := Totals{Today: 2, Week: 14, Month: 2}
+	if got != want {
+		t.Errorf("Totals() = %+v, want %+v", got, want)
+	}
+}
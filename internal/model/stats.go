@@ -8,6 +8,9 @@ type StatsCache struct {
 	TotalMessages    int             `json:"totalMessages"`
 	DailyActivity    []DailyActivity `json:"dailyActivity"`
 	HourCounts       map[string]int  `json:"hourCounts"`
+
+	// DayStartHour DailyActivity 日期划分使用的起始小时; stats-cache.json 按日历日期, 为 0
+	DayStartHour int `json:"-"`
 }
 
 // DailyActivity 每日活动记录
@@ -15,6 +18,9 @@ type DailyActivity struct {
 	Date         string `json:"date"`
 	MessageCount int    `json:"messageCount"`
 	SessionCount int    `json:"sessionCount"`
+
+	// SessionIDs 当天有消息的会话; 仅会话记录索引提供, 用于统计区间内的不重复会话数
+	SessionIDs []string `json:"-"`
 }
 
 // StatsInfo 解析后的统计摘要
@@ -29,4 +35,23 @@ type StatsInfo struct {
 	PeakHour      int
 	PeakCount     int
 	HasPeakHour   bool
+	DailyMessages []int   // 最近 30 天每日消息数 (最早在前, 最后一项为今天)
+	HourCounts    [24]int // 0-23 点的会话数
+	Records       Records
+	NewRecords    []string // 今天刷新的纪录类型 (streak/messages/sessions)
+	ActiveDays7   int      // 最近 7 天 (含今天) 活跃天数
+	ActiveDays30  int      // 最近 30 天 (含今天) 活跃天数
+	AvgMessages7  float64  // 今天之前 7 天的日均消息数 (无活动的日期计 0)
+	FreezesLeft   int      // 本月剩余可冻结天数 (连续活跃规则)
+}
+
+// Records 历史纪录
+type Records struct {
+	LongestStreak      int    `json:"longest_streak"`       // 最长连续活跃天数
+	LongestStreakStart string `json:"longest_streak_start"` // 最长连续活跃开始日期 (YYYY-MM-DD)
+	LongestStreakEnd   string `json:"longest_streak_end"`
+	MostMessages       int    `json:"most_messages"` // 单日最多消息数
+	MostMessagesDate   string `json:"most_messages_date"`
+	MostSessions       int    `json:"most_sessions"` // 单日最多会话数
+	MostSessionsDate   string `json:"most_sessions_date"`
 }
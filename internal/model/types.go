@@ -3,14 +3,17 @@ package model
 
 // SessionData 表示 Claude Code 通过 stdin 传入的完整会话数据
 type SessionData struct {
-	Model         ModelInfo      `json:"model"`
-	Workspace     WorkspaceInfo  `json:"workspace"`
-	Cost          CostInfo       `json:"cost"`
-	ContextWindow ContextWindow  `json:"context_window"`
+	SessionID      string        `json:"session_id"`
+	TranscriptPath string        `json:"transcript_path"` // 当前会话记录 (JSONL) 路径
+	Model          ModelInfo     `json:"model"`
+	Workspace      WorkspaceInfo `json:"workspace"`
+	Cost           CostInfo      `json:"cost"`
+	ContextWindow  ContextWindow `json:"context_window"`
 }
 
 // ModelInfo 模型信息
 type ModelInfo struct {
+	ID          string `json:"id"`
 	DisplayName string `json:"display_name"`
 }
 
@@ -21,10 +24,10 @@ type WorkspaceInfo struct {
 
 // CostInfo 成本和代码变更信息
 type CostInfo struct {
-	TotalCostUSD     float64 `json:"total_cost_usd"`
-	TotalLinesAdded  int     `json:"total_lines_added"`
-	TotalLinesRemoved int    `json:"total_lines_removed"`
-	TotalDurationMs  int64   `json:"total_duration_ms"`
+	TotalCostUSD      float64 `json:"total_cost_usd"`
+	TotalLinesAdded   int     `json:"total_lines_added"`
+	TotalLinesRemoved int     `json:"total_lines_removed"`
+	TotalDurationMs   int64   `json:"total_duration_ms"`
 }
 
 // ContextWindow 上下文窗口信息
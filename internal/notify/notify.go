@@ -0,0 +1,75 @@
+// Package notify 通过终端转义序列发送处理完成通知
+//
+// 通知直接写入 /dev/tty, 不依赖桌面通知服务:
+//   - BEL: 终端响铃, 多数终端会标记标签页或弹跳 Dock 图标
+//   - OSC 9: iTerm2、WezTerm、Windows Terminal 等支持的桌面通知
+//   - OSC 777: rxvt-unicode、foot、Ghostty 等支持的带标题桌面通知
+package notify
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/config"
+	"github.com/nyan-statusline-cc/internal/formatter"
+)
+
+const notifyTitle = "Claude Code"
+
+// TurnCompleted 处理时长达到阈值时发送完成通知
+// Parameters:
+//   - cfg: 通知配置
+//   - elapsed: 本轮处理时长
+//
+// Return:
+//   - error: 写入终端错误, 未达阈值或未开启任何通知方式时返回 nil
+func TurnCompleted(cfg config.NotifyConfig, elapsed time.Duration) error {
+	if elapsed < time.Duration(cfg.MinDurationSec)*time.Second {
+		return nil
+	}
+	body := "🐱 处理完成 meow~ (" + formatter.FormatDuration(elapsed.Milliseconds()) + ")"
+	seq := buildSequence(cfg, notifyTitle, body)
+	if seq == "" {
+		return nil
+	}
+
+	// hooks 的 stdout/stderr 被 Claude Code 捕获, 需直接写入终端
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+	_, err = tty.WriteString(seq)
+	return err
+}
+
+// buildSequence 根据配置拼接通知转义序列
+func buildSequence(cfg config.NotifyConfig, title, body string) string {
+	title, body = sanitize(title), sanitize(body)
+
+	var b strings.Builder
+	if cfg.Bell {
+		b.WriteString("\a")
+	}
+	if cfg.OSC9 {
+		b.WriteString("\033]9;" + title + ": " + body + "\a")
+	}
+	if cfg.OSC777 {
+		b.WriteString("\033]777;notify;" + title + ";" + body + "\a")
+	}
+	return b.String()
+}
+
+// sanitize 移除会提前终止 OSC 序列的控制字符和分号 (OSC 777 字段分隔符)
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			return -1
+		case r == ';':
+			return ','
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/config"
+)
+
+// TestBuildSequence_NoneEnabled 未开启任何通知方式时返回空串
+func TestBuildSequence_NoneEnabled(t *testing.T) {
+	if got := buildSequence(config.NotifyConfig{}, "t", "b"); got != "" {
+		t.Errorf("buildSequence() = %q, want empty", got)
+	}
+}
+
+// TestBuildSequence_AllEnabled 验证 BEL、OSC 9、OSC 777 序列格式
+func TestBuildSequence_AllEnabled(t *testing.T) {
+	cfg := config.NotifyConfig{Bell: true, OSC9: true, OSC777: true}
+	got := buildSequence(cfg, "Claude Code", "done")
+	want := "\a" + "\033]9;Claude Code: done\a" + "\033]777;notify;Claude Code;done\a"
+	if got != want {
+		t.Errorf("buildSequence() = %q, want %q", got, want)
+	}
+}
+
+// TestBuildSequence_Sanitize 控制字符和分号不应破坏 OSC 序列
+func TestBuildSequence_Sanitize(t *testing.T) {
+	cfg := config.NotifyConfig{OSC777: true}
+	got := buildSequence(cfg, "a;b", "x\ay\033z")
+	if strings.Count(got, ";") != 3 {
+		t.Errorf("OSC 777 should have exactly 3 separators, got %q", got)
+	}
+	if strings.Count(got, "\a") != 1 || strings.Count(got, "\033") != 1 {
+		t.Errorf("body control characters should be stripped, got %q", got)
+	}
+}
+
+// TestTurnCompleted_BelowThreshold 未达阈值时不写终端
+func TestTurnCompleted_BelowThreshold(t *testing.T) {
+	cfg := config.NotifyConfig{MinDurationSec: 60, Bell: true}
+	if err := TurnCompleted(cfg, 10*time.Second); err != nil {
+		t.Errorf("TurnCompleted() below threshold error: %v", err)
+	}
+}
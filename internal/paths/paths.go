@@ -0,0 +1,111 @@
+// Package paths 统一解析 Claude Code 数据目录与 nyan-statusline 自身数据文件的位置
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// legacyMarkers 二进制同目录存在任一文件时, 沿用旧版 "数据与二进制同目录" 布局
+var legacyMarkers = []string{"nyan-config.json", "nyan-state.json"}
+
+// Paths 数据目录解析结果
+type Paths struct {
+	ClaudeDir string // Claude Code 数据目录: stats-cache.json, projects/
+	DataDir   string // nyan-statusline 数据目录: nyan-config.json, 状态文件, 账本, sprites/
+
+	explicit bool // ClaudeDir 来自 --data-dir, 不再被配置覆盖
+}
+
+// Options 解析参数
+type Options struct {
+	DataDir   string // --data-dir 显式指定的目录, 优先级最高
+	BinaryDir string // 二进制所在目录, 用于兼容旧版布局
+}
+
+// Resolve 解析数据目录
+// ClaudeDir 优先级: --data-dir > CLAUDE_CONFIG_DIR > $XDG_CONFIG_HOME/claude (存在时) > ~/.claude
+// DataDir 优先级: --data-dir > 含旧版数据文件的二进制目录 > ClaudeDir
+// Parameters:
+//   - opts: 解析参数
+//
+// Return:
+//   - Paths: 解析后的目录
+func Resolve(opts Options) Paths {
+	home, _ := os.UserHomeDir()
+	return resolve(opts, os.Getenv, home)
+}
+
+// resolve Resolve 的可测试实现, 环境变量与家目录由调用方注入
+func resolve(opts Options, getenv func(string) string, home string) Paths {
+	if opts.DataDir != "" {
+		dir := expandHome(opts.DataDir, home)
+		return Paths{ClaudeDir: dir, DataDir: dir, explicit: true}
+	}
+
+	p := Paths{ClaudeDir: claudeDir(getenv, home)}
+	p.DataDir = p.ClaudeDir
+	if opts.BinaryDir != "" && hasLegacyData(opts.BinaryDir) {
+		p.DataDir = opts.BinaryDir
+	}
+	return p
+}
+
+// claudeDir 按环境变量与 XDG 约定定位 Claude Code 数据目录
+func claudeDir(getenv func(string) string, home string) string {
+	if env := getenv("CLAUDE_CONFIG_DIR"); env != "" {
+		// CLAUDE_CONFIG_DIR 可为逗号分隔的多个目录, 取第一个
+		first, _, _ := strings.Cut(env, ",")
+		return expandHome(strings.TrimSpace(first), home)
+	}
+	xdg := getenv("XDG_CONFIG_HOME")
+	if xdg == "" && home != "" {
+		xdg = filepath.Join(home, ".config")
+	}
+	if xdg != "" {
+		if dir := filepath.Join(xdg, "claude"); isDir(dir) {
+			return dir
+		}
+	}
+	return filepath.Join(home, ".claude")
+}
+
+// ApplyConfig 应用配置文件中的 data_dir, 仅改变 Claude Code 数据目录
+// --data-dir 显式指定时忽略配置; nyan-config.json 本身仍从 DataDir 读取
+// Parameters:
+//   - dataDir: 配置中的 data_dir, 为空时不做修改
+func (p *Paths) ApplyConfig(dataDir string) {
+	if p.explicit || dataDir == "" {
+		return
+	}
+	home, _ := os.UserHomeDir()
+	p.ClaudeDir = expandHome(dataDir, home)
+}
+
+// expandHome 展开路径开头的 "~"
+func expandHome(path, home string) string {
+	if path == "~" {
+		return home
+	}
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
+
+// hasLegacyData 判断目录中是否存在旧版布局的数据文件
+func hasLegacyData(dir string) bool {
+	for _, name := range legacyMarkers {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isDir 判断路径是否为已存在的目录
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
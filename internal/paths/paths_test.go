@@ -0,0 +1,98 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// envOf 构造测试用的环境变量读取函数
+func envOf(m map[string]string) func(string) string {
+	return func(k string) string { return m[k] }
+}
+
+// TestResolve_Default 无任何配置时使用 ~/.claude
+func TestResolve_Default(t *testing.T) {
+	home := t.TempDir()
+	p := resolve(Options{}, envOf(nil), home)
+
+	want := filepath.Join(home, ".claude")
+	if p.ClaudeDir != want || p.DataDir != want {
+		t.Errorf("resolve() = %+v, want both %q", p, want)
+	}
+}
+
+// TestResolve_ClaudeConfigDir CLAUDE_CONFIG_DIR 优先于 XDG 与家目录, 多值取第一个
+func TestResolve_ClaudeConfigDir(t *testing.T) {
+	home := t.TempDir()
+	p := resolve(Options{}, envOf(map[string]string{"CLAUDE_CONFIG_DIR": "~/work-claude, /other"}), home)
+
+	if want := filepath.Join(home, "work-claude"); p.ClaudeDir != want {
+		t.Errorf("ClaudeDir = %q, want %q", p.ClaudeDir, want)
+	}
+}
+
+// TestResolve_XDG $XDG_CONFIG_HOME/claude 仅在存在时使用
+func TestResolve_XDG(t *testing.T) {
+	home := t.TempDir()
+	xdg := t.TempDir()
+	env := envOf(map[string]string{"XDG_CONFIG_HOME": xdg})
+
+	if p := resolve(Options{}, env, home); p.ClaudeDir != filepath.Join(home, ".claude") {
+		t.Errorf("missing XDG dir: ClaudeDir = %q, want ~/.claude", p.ClaudeDir)
+	}
+
+	if err := os.Mkdir(filepath.Join(xdg, "claude"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if p := resolve(Options{}, env, home); p.ClaudeDir != filepath.Join(xdg, "claude") {
+		t.Errorf("existing XDG dir: ClaudeDir = %q, want %q", p.ClaudeDir, filepath.Join(xdg, "claude"))
+	}
+}
+
+// TestResolve_Explicit --data-dir 优先级最高且不被配置覆盖
+func TestResolve_Explicit(t *testing.T) {
+	dir := t.TempDir()
+	p := resolve(Options{DataDir: dir}, envOf(map[string]string{"CLAUDE_CONFIG_DIR": "/env"}), t.TempDir())
+	p.ApplyConfig("/from-config")
+
+	if p.ClaudeDir != dir || p.DataDir != dir {
+		t.Errorf("resolve() = %+v, want both %q", p, dir)
+	}
+}
+
+// TestResolve_LegacyBinaryDir 二进制目录中已有数据文件时继续使用该目录存放 nyan 数据
+func TestResolve_LegacyBinaryDir(t *testing.T) {
+	home := t.TempDir()
+	bin := t.TempDir()
+
+	if p := resolve(Options{BinaryDir: bin}, envOf(nil), home); p.DataDir == bin {
+		t.Error("empty binary dir should not be used as DataDir")
+	}
+
+	if err := os.WriteFile(filepath.Join(bin, "nyan-config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := resolve(Options{BinaryDir: bin}, envOf(nil), home)
+	if p.DataDir != bin {
+		t.Errorf("DataDir = %q, want legacy binary dir %q", p.DataDir, bin)
+	}
+	if p.ClaudeDir != filepath.Join(home, ".claude") {
+		t.Errorf("ClaudeDir = %q, want ~/.claude", p.ClaudeDir)
+	}
+}
+
+// TestApplyConfig 配置中的 data_dir 只改变 Claude Code 数据目录
+func TestApplyConfig(t *testing.T) {
+	p := resolve(Options{}, envOf(nil), t.TempDir())
+	dataDir := p.DataDir
+
+	p.ApplyConfig("")
+	if p.ClaudeDir != dataDir {
+		t.Errorf("empty data_dir should not change ClaudeDir, got %q", p.ClaudeDir)
+	}
+	p.ApplyConfig("/srv/claude")
+	if p.ClaudeDir != "/srv/claude" || p.DataDir != dataDir {
+		t.Errorf("ApplyConfig() = %+v, want ClaudeDir /srv/claude and DataDir unchanged", p)
+	}
+}
@@ -0,0 +1,77 @@
+// Package pricing 维护按模型计价的价格表, 根据 token 用量估算 API 等价成本
+//
+// 订阅计划的会话 total_cost_usd 可能为 0, 此时按公开 API 价格估算花费.
+// 价格表按 model.id 子串匹配 (取最长匹配), 兼容带日期后缀和云厂商前缀的模型 ID,
+// 如 "claude-sonnet-4-20250514"、"us.anthropic.claude-opus-4-1-20250805-v1:0".
+package pricing
+
+import "strings"
+
+// Price 每百万 token 的美元价格
+type Price struct {
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheWrite float64 `json:"cache_write"` // 缓存写入 (5 分钟 TTL)
+	CacheRead  float64 `json:"cache_read"`
+}
+
+// builtinPrices 内置价格表, key 为 model.id 子串
+var builtinPrices = map[string]Price{
+	"claude-opus-4-5":   {Input: 5, Output: 25, CacheWrite: 6.25, CacheRead: 0.5},
+	"claude-opus-4":     {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.5},
+	"claude-sonnet-4":   {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+	"claude-haiku-4-5":  {Input: 1, Output: 5, CacheWrite: 1.25, CacheRead: 0.1},
+	"claude-3-opus":     {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.5},
+	"claude-3-7-sonnet": {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+	"claude-3-5-sonnet": {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+	"claude-3-5-haiku":  {Input: 0.8, Output: 4, CacheWrite: 1, CacheRead: 0.08},
+	"claude-3-haiku":    {Input: 0.25, Output: 1.25, CacheWrite: 0.3, CacheRead: 0.03},
+}
+
+// Lookup 查找模型价格, 用户价格表优先于内置价格表
+// Parameters:
+//   - modelID: 模型 ID (model.id)
+//   - overrides: 用户价格表, key 为 model.id 子串, 可为 nil
+//
+// Return:
+//   - Price: 价格
+//   - bool: 是否找到
+func Lookup(modelID string, overrides map[string]Price) (Price, bool) {
+	if modelID == "" {
+		return Price{}, false
+	}
+	if p, ok := longestMatch(modelID, overrides); ok {
+		return p, true
+	}
+	return longestMatch(modelID, builtinPrices)
+}
+
+// longestMatch 返回 key 为 modelID 子串且最长的价格
+func longestMatch(modelID string, table map[string]Price) (Price, bool) {
+	var best string
+	for key := range table {
+		if len(key) > len(best) && strings.Contains(modelID, key) {
+			best = key
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return table[best], true
+}
+
+// Cost 按价格计算 token 用量的美元成本
+// Parameters:
+//   - input: 输入 token (不含缓存)
+//   - output: 输出 token
+//   - cacheWrite: 缓存写入 token
+//   - cacheRead: 缓存读取 token
+//
+// Return:
+//   - float64: 成本 (USD)
+func (p Price) Cost(input, output, cacheWrite, cacheRead int64) float64 {
+	return (float64(input)*p.Input +
+		float64(output)*p.Output +
+		float64(cacheWrite)*p.CacheWrite +
+		float64(cacheRead)*p.CacheRead) / 1e6
+}
@@ -0,0 +1,51 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLookup_Builtin(t *testing.T) {
+	tests := []struct {
+		id        string
+		wantInput float64
+	}{
+		{"claude-sonnet-4-20250514", 3},
+		{"claude-opus-4-1-20250805", 15},
+		{"claude-opus-4-5-20251101", 5}, // 最长匹配优先于 claude-opus-4
+		{"us.anthropic.claude-3-5-haiku-20241022-v1:0", 0.8},
+	}
+	for _, tt := range tests {
+		p, ok := Lookup(tt.id, nil)
+		if !ok || p.Input != tt.wantInput {
+			t.Errorf("Lookup(%q) = %+v %v, want input %v", tt.id, p, ok, tt.wantInput)
+		}
+	}
+}
+
+func TestLookup_Unknown(t *testing.T) {
+	if _, ok := Lookup("gpt-4o", nil); ok {
+		t.Error("Lookup(unknown) should not match")
+	}
+	if _, ok := Lookup("", nil); ok {
+		t.Error("Lookup(\"\") should not match")
+	}
+}
+
+// TestLookup_Override 用户价格表优先, 即使内置表有更长的匹配
+func TestLookup_Override(t *testing.T) {
+	overrides := map[string]Price{"claude": {Input: 1}}
+	p, ok := Lookup("claude-sonnet-4-20250514", overrides)
+	if !ok || p.Input != 1 {
+		t.Errorf("Lookup with override = %+v %v, want input 1", p, ok)
+	}
+}
+
+func TestPrice_Cost(t *testing.T) {
+	p := Price{Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3}
+	// 1M 输入 + 0.1M 输出 + 0.2M 缓存写入 + 2M 缓存读取 = 3 + 1.5 + 0.75 + 0.6
+	got := p.Cost(1000000, 100000, 200000, 2000000)
+	if math.Abs(got-5.85) > 1e-9 {
+		t.Errorf("Cost() = %f, want 5.85", got)
+	}
+}
@@ -0,0 +1,61 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/nyan-statusline-cc/internal/achievement"
+	"github.com/nyan-statusline-cc/internal/formatter"
+)
+
+// rarityNames 稀有度名称与颜色
+var rarityNames = map[int][2]string{
+	achievement.Common:    {"普通", White},
+	achievement.Rare:      {"稀有", Blue},
+	achievement.Epic:      {"史诗", Magenta},
+	achievement.Legendary: {"传说", Yellow},
+}
+
+// achievementBarWidth 未解锁徽章进度条宽度
+const achievementBarWidth = 10
+
+// AchievementsReport 将徽章列表渲染为终端文本
+// Parameters:
+//   - c: 徽章列表与完成度
+//
+// Return:
+//   - string: 带 ANSI 颜色的多行列表
+func AchievementsReport(c *achievement.Catalog) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s\n\n", Colorize(Bold+"🏆 成就", Magenta),
+		Colorize(fmt.Sprintf("%d/%d (%.0f%%)", c.Unlocked, c.Total, c.CompletionPct), Black))
+
+	for _, s := range c.Badges {
+		rarity := rarityNames[s.Rarity]
+		name := s.Icon + " " + s.Name
+		if !s.Unlocked {
+			// 未解锁: 图标变暗, 名称后加锁, 仍能看出是哪个徽章
+			name = Colorize(s.Icon, Dim) + " " + Colorize(s.Name+" 🔒", Black)
+		}
+		fmt.Fprintf(&b, "  %s  %s  %s\n", name, Colorize(rarity[0], rarity[1]), Colorize(s.Desc, Black))
+
+		status := "未解锁"
+		switch {
+		case s.Unlocked:
+			status = Colorize("✅ "+s.Date+" 解锁", Green)
+		case s.Current != nil:
+			status = achievementBar(s.Progress) + " " +
+				formatter.FormatCount(int64(*s.Current)) + "/" + formatter.FormatCount(int64(s.Threshold))
+		}
+		fmt.Fprintf(&b, "     %s · %s\n", s.Condition, status)
+	}
+	return b.String()
+}
+
+// achievementBar 进度条, 如 "▰▰▰▰▱▱▱▱▱▱"
+func achievementBar(ratio float64) string {
+	filled := min(achievementBarWidth, int(math.Floor(ratio*achievementBarWidth)))
+	return Colorize(strings.Repeat("▰", filled), Yellow) +
+		Colorize(strings.Repeat("▱", achievementBarWidth-filled), Black)
+}
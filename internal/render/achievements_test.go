@@ -0,0 +1,32 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nyan-statusline-cc/internal/achievement"
+)
+
+func TestAchievementsReport(t *testing.T) {
+	m := achievement.Metrics{achievement.MetricMessages: 250}
+	unlocked := []achievement.Unlocked{{Badge: achievement.Badges[0], Date: "2026-02-01"}}
+	out := AchievementsReport(achievement.BuildCatalog(unlocked, m))
+
+	for _, want := range []string{"🏆 成就", "1/12 (8%)", "🥈 话唠新星", "2026-02-01 解锁", Dim + "🥇" + Reset, "消息达人 🔒", "250/500", "单会话时长 ≥ 120分钟", "未解锁"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("AchievementsReport() missing %q", want)
+		}
+	}
+}
+
+func TestAchievementBar(t *testing.T) {
+	for _, tc := range []struct {
+		ratio        float64
+		filled, rest int
+	}{{0, 0, 10}, {0.55, 5, 5}, {1, 10, 0}} {
+		got := achievementBar(tc.ratio)
+		if strings.Count(got, "▰") != tc.filled || strings.Count(got, "▱") != tc.rest {
+			t.Errorf("achievementBar(%.2f) = %q, want %d filled", tc.ratio, got, tc.filled)
+		}
+	}
+}
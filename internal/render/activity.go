@@ -0,0 +1,88 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/nyan-statusline-cc/internal/achievement"
+	"github.com/nyan-statusline-cc/internal/animation"
+	"github.com/nyan-statusline-cc/internal/config"
+	"github.com/nyan-statusline-cc/internal/formatter"
+	"github.com/nyan-statusline-cc/internal/model"
+)
+
+// sparklineSegment 渲染最近 days 天的消息趋势图, 如 "📊 ▁▂▅█▃▁▇"
+// days 超出序列长度时取全部, 序列全为 0 时返回空串
+func sparklineSegment(daily []int, days int) string {
+	if days <= 0 || days > len(daily) {
+		days = len(daily)
+	}
+	series := daily[len(daily)-days:]
+	total := 0
+	for _, v := range series {
+		total += v
+	}
+	if total == 0 {
+		return ""
+	}
+	return Colorize("📊 "+animation.Sparkline(series), Cyan)
+}
+
+// recordsText 格式化历史纪录, 如 "🏆 最长14连 · 单日186消息/9会话"
+func recordsText(r model.Records) string {
+	count := func(n int) string { return formatter.FormatCount(int64(n)) }
+	return "🏆 最长" + count(r.LongestStreak) + "连 · 单日" +
+		count(r.MostMessages) + "消息/" + count(r.MostSessions) + "会话"
+}
+
+// streakText 连续活跃段, 附带生效的规则, 如 "⚡ 12连 工作日 ≥5条 ❄️1"
+func streakText(info *model.StatsInfo, rule config.StreakConfig) string {
+	text := "⚡ " + formatter.FormatCount(int64(info.Streak)) + "连"
+	if rule.WorkdaysOnly {
+		text += " 工作日"
+	}
+	if rule.MinMessages > 0 {
+		text += " ≥" + formatter.FormatCount(int64(rule.MinMessages)) + "条"
+	}
+	if rule.FreezesPerMonth > 0 {
+		text += fmt.Sprintf(" ❄️%d", info.FreezesLeft)
+	}
+	return text
+}
+
+// recentActiveText 近期活跃天数, 如 "🗓️ 5/7天 · 18/30天"
+func recentActiveText(info *model.StatsInfo) string {
+	return fmt.Sprintf("🗓️ %d/7天 · %d/30天", info.ActiveDays7, info.ActiveDays30)
+}
+
+// todayDelta 今日消息与之前 7 天日均的对比, 如 " (↑30% vs 7日均)"; 无基线时返回空串
+func todayDelta(info *model.StatsInfo) string {
+	if info.AvgMessages7 <= 0 {
+		return ""
+	}
+	pct := (float64(info.TodayMessages) - info.AvgMessages7) / info.AvgMessages7 * 100
+	text, color := fmt.Sprintf("↑%.0f%%", pct), Green
+	if pct < 0 {
+		text, color = fmt.Sprintf("↓%.0f%%", math.Abs(pct)), Red
+	}
+	return " " + Colorize("("+text+" vs 7日均)", color)
+}
+
+// progressSegment 渲染成就升级进度, 如 "🥈→🥇 432/500消息"
+// mode 为 all 时显示全部系列, 否则只显示最接近升级的系列; 全部满级时返回空串
+func progressSegment(unlocked []achievement.Unlocked, m achievement.Metrics, mode string) string {
+	list := achievement.NextTiers(unlocked, m)
+	if mode != achievement.ProgressAll {
+		nearest := achievement.Nearest(list)
+		if nearest == nil {
+			return ""
+		}
+		list = []achievement.Progress{*nearest}
+	}
+	texts := make([]string, len(list))
+	for i, p := range list {
+		texts[i] = p.Text()
+	}
+	return Colorize(strings.Join(texts, " "), Yellow)
+}
@@ -0,0 +1,96 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nyan-statusline-cc/internal/achievement"
+	"github.com/nyan-statusline-cc/internal/config"
+	"github.com/nyan-statusline-cc/internal/model"
+)
+
+func TestSparklineSegment(t *testing.T) {
+	daily := make([]int, 30)
+	daily[27], daily[28], daily[29] = 2, 8, 4
+
+	got := sparklineSegment(daily, 3)
+	if !strings.Contains(got, "📊 ▃█▅") {
+		t.Errorf("sparklineSegment(3 days) = %q, want ▃█▅", got)
+	}
+	if got := sparklineSegment(daily, 99); !strings.Contains(got, strings.Repeat("▁", 27)+"▃█▅") {
+		t.Errorf("sparklineSegment(99 days) = %q, want whole 30-day series", got)
+	}
+	if got := sparklineSegment(make([]int, 30), 7); got != "" {
+		t.Errorf("sparklineSegment(no activity) = %q, want empty", got)
+	}
+}
+
+func TestRecordsText(t *testing.T) {
+	r := model.Records{LongestStreak: 14, MostMessages: 186, MostSessions: 9}
+	if got := recordsText(r); got != "🏆 最长14连 · 单日186消息/9会话" {
+		t.Errorf("recordsText() = %q", got)
+	}
+}
+
+func TestProgressSegment(t *testing.T) {
+	m := achievement.Metrics{achievement.MetricMessages: 90, achievement.MetricSessions: 10}
+
+	got := progressSegment(nil, m, achievement.ProgressNearest)
+	if !strings.Contains(got, "🥈 90/100消息") || strings.Contains(got, "会话") {
+		t.Errorf("progressSegment(nearest) = %q, want only messages track", got)
+	}
+	got = progressSegment(nil, m, achievement.ProgressAll)
+	for _, want := range []string{"🥈 90/100消息", "⭐ 10/50会话", "✊ 0/3连", "🎖️ 0/30天"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("progressSegment(all) = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestRecentActiveText(t *testing.T) {
+	info := &model.StatsInfo{ActiveDays7: 5, ActiveDays30: 18}
+	if got := recentActiveText(info); got != "🗓️ 5/7天 · 18/30天" {
+		t.Errorf("recentActiveText() = %q", got)
+	}
+}
+
+func TestTodayDelta(t *testing.T) {
+	tests := []struct {
+		name  string
+		info  model.StatsInfo
+		want  string
+		color string
+	}{
+		{"no baseline", model.StatsInfo{TodayMessages: 45}, "", ""},
+		{"up", model.StatsInfo{TodayMessages: 39, AvgMessages7: 30}, "(↑30% vs 7日均)", Green},
+		{"down", model.StatsInfo{TodayMessages: 15, AvgMessages7: 30}, "(↓50% vs 7日均)", Red},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := todayDelta(&tt.info)
+			if !strings.Contains(got, tt.want) || (tt.want == "") != (got == "") {
+				t.Errorf("todayDelta() = %q, want %q", got, tt.want)
+			}
+			if tt.color != "" && !strings.Contains(got, tt.color) {
+				t.Errorf("todayDelta() = %q, want color %q", got, tt.color)
+			}
+		})
+	}
+}
+
+func TestStreakText(t *testing.T) {
+	info := &model.StatsInfo{Streak: 12, FreezesLeft: 1}
+	tests := []struct {
+		rule config.StreakConfig
+		want string
+	}{
+		{config.StreakConfig{}, "⚡ 12连"},
+		{config.StreakConfig{WorkdaysOnly: true}, "⚡ 12连 工作日"},
+		{config.StreakConfig{WorkdaysOnly: true, MinMessages: 5, FreezesPerMonth: 2}, "⚡ 12连 工作日 ≥5条 ❄️1"},
+	}
+	for _, tt := range tests {
+		if got := streakText(info, tt.rule); got != tt.want {
+			t.Errorf("streakText(%+v) = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import "fmt"
 const (
 	Reset   = "\033[0m"
 	Bold    = "\033[1m"
+	Dim     = "\033[2m"
 	Black   = "\033[90m"
 	Red     = "\033[91m"
 	Green   = "\033[92m"
@@ -42,3 +43,18 @@ func ContextColor(percent float64) string {
 	}
 	return Red
 }
+
+// BudgetColor 根据预算使用比例返回对应颜色
+// Parameters:
+//   - ratio: 花费 / 预算
+//
+// Return:
+//   - string: ANSI 颜色代码 (< 80% 绿, < 100% 黄, 超出红)
+func BudgetColor(ratio float64) string {
+	if ratio < 0.8 {
+		return Green
+	} else if ratio < 1 {
+		return Yellow
+	}
+	return Red
+}
@@ -102,3 +102,23 @@ func TestContextColor_HighUsage(t *testing.T) {
 		t.Errorf("ContextColor(100) should be Red, got %q", c)
 	}
 }
+
+// TestBudgetColor 验证预算使用比例的颜色阈值
+func TestBudgetColor(t *testing.T) {
+	tests := []struct {
+		ratio float64
+		want  string
+	}{
+		{0, Green},
+		{0.79, Green},
+		{0.8, Yellow},
+		{0.99, Yellow},
+		{1, Red},
+		{2.5, Red},
+	}
+	for _, tt := range tests {
+		if got := BudgetColor(tt.ratio); got != tt.want {
+			t.Errorf("BudgetColor(%v) = %q, want %q", tt.ratio, got, tt.want)
+		}
+	}
+}
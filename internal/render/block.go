@@ -0,0 +1,44 @@
+package render
+
+import (
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/animation"
+	"github.com/nyan-statusline-cc/internal/config"
+	"github.com/nyan-statusline-cc/internal/formatter"
+	"github.com/nyan-statusline-cc/internal/paths"
+	"github.com/nyan-statusline-cc/internal/usage"
+)
+
+// blockBarWidth 用量窗口进度条宽度
+const blockBarWidth = 6
+
+// renderBlock 渲染当前滚动用量窗口段, 无活跃窗口时返回空串
+func renderBlock(cfg *config.Config, p paths.Paths, now time.Time) string {
+	if cfg.Block.Hours <= 0 {
+		return ""
+	}
+	length := time.Duration(cfg.Block.Hours * float64(time.Hour))
+	b := usage.CurrentBlock(p.ClaudeDir, p.DataDir, length, now)
+	if b == nil {
+		return ""
+	}
+	return blockSegment(b, cfg, now)
+}
+
+// blockSegment 格式化用量窗口, 如 "🕔 ███▍░░ 1.2M/2M ↻2h15m"
+func blockSegment(b *usage.Block, cfg *config.Config, now time.Time) string {
+	reset := "↻" + formatter.FormatDuration(b.Remaining(now).Milliseconds())
+	tokens := formatter.FormatTokens(b.Tokens)
+	if cfg.Block.TokenLimit <= 0 {
+		return Colorize("🕔 "+tokens+" "+reset, Blue)
+	}
+
+	pct := float64(b.Tokens) / float64(cfg.Block.TokenLimit) * 100
+	opts := barOptions(cfg)
+	opts.Width = blockBarWidth
+	opts.Marker = 0
+	bar := animation.ProgressBar(pct, opts)
+	text := tokens + "/" + formatter.FormatTokens(cfg.Block.TokenLimit) + " " + reset
+	return "🕔 " + bar + " " + Colorize(text, ContextColor(pct))
+}
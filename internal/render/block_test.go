@@ -0,0 +1,31 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/config"
+	"github.com/nyan-statusline-cc/internal/usage"
+)
+
+// TestBlockSegment 验证有无 token 上限时的窗口显示
+func TestBlockSegment(t *testing.T) {
+	now := time.Date(2026, 2, 26, 15, 0, 0, 0, time.UTC)
+	b := &usage.Block{Start: now.Add(-time.Hour), End: now.Add(4 * time.Hour), Tokens: 1500000}
+
+	cfg := config.Default()
+	got := blockSegment(b, cfg, now)
+	if !strings.Contains(got, "↻4h0m") || strings.Contains(got, "/") {
+		t.Errorf("blockSegment() without limit = %q", got)
+	}
+
+	cfg.Block.TokenLimit = 2000000
+	got = blockSegment(b, cfg, now)
+	if !strings.Contains(got, "/") || !strings.Contains(got, "█") {
+		t.Errorf("blockSegment() with limit should show usage and bar, got %q", got)
+	}
+	if !strings.Contains(got, Yellow) {
+		t.Errorf("75%% usage should be yellow, got %q", got)
+	}
+}
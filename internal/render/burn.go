@@ -0,0 +1,69 @@
+package render
+
+import (
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/burn"
+	"github.com/nyan-statusline-cc/internal/config"
+	"github.com/nyan-statusline-cc/internal/formatter"
+	"github.com/nyan-statusline-cc/internal/model"
+)
+
+// renderBurn 渲染消耗速率段: 成本/小时、输出 token/分钟、上下文填满预估
+func renderBurn(data *model.SessionData, cfg *config.Config, dataDir string, now time.Time) []string {
+	wantCost := cfg.IsLine1Enabled("burnRate")
+	wantOutput := cfg.IsLine1Enabled("outputRate")
+	wantEta := cfg.IsLine1Enabled("contextEta")
+	if !wantCost && !wantOutput && !wantEta {
+		return nil
+	}
+
+	u := calcContextUsage(data)
+	sample := burn.Sample{
+		At:            now.UnixMilli(),
+		CostUSD:       data.Cost.TotalCostUSD,
+		OutputTokens:  data.ContextWindow.TotalOutputTokens,
+		ContextTokens: u.Used,
+	}
+	samples, _ := burn.Record(dataDir, data.SessionID, sample)
+	rates := sessionRates(data, burn.Compute(samples))
+
+	var parts []string
+	if wantCost && rates.CostPerHour > 0 {
+		parts = append(parts, Colorize("🔥 "+formatter.FormatCost(rates.CostPerHour)+"/h", Yellow))
+	}
+	if wantOutput && rates.OutputPerMinute > 0 {
+		parts = append(parts, Colorize("✍️ "+formatter.FormatTokens(int64(rates.OutputPerMinute))+"/min", Cyan))
+	}
+	if wantEta {
+		if eta, ok := burn.TimeToFull(rates, u.Remaining()); ok {
+			parts = append(parts, Colorize("🪫 "+formatter.FormatDuration(eta.Milliseconds())+" to full", etaColor(eta)))
+		}
+	}
+	return parts
+}
+
+// sessionRates 近期快照不足时, 成本和输出速率退化为整个会话的平均值
+func sessionRates(data *model.SessionData, r burn.Rates) burn.Rates {
+	if r.HasRates {
+		return r
+	}
+	elapsed := time.Duration(data.Cost.TotalDurationMs) * time.Millisecond
+	if elapsed < time.Minute {
+		return r
+	}
+	r.CostPerHour = data.Cost.TotalCostUSD / elapsed.Hours()
+	r.OutputPerMinute = float64(data.ContextWindow.TotalOutputTokens) / elapsed.Minutes()
+	return r
+}
+
+// etaColor 上下文即将填满时变红
+func etaColor(eta time.Duration) string {
+	switch {
+	case eta < 10*time.Minute:
+		return Red
+	case eta < 30*time.Minute:
+		return Yellow
+	}
+	return Green
+}
@@ -0,0 +1,42 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/burn"
+)
+
+// TestSessionRates_Fallback 快照不足时使用会话平均速率
+func TestSessionRates_Fallback(t *testing.T) {
+	data := newTestSessionData()
+	data.Cost.TotalCostUSD = 1.5
+	data.Cost.TotalDurationMs = int64(30 * time.Minute / time.Millisecond)
+	data.ContextWindow.TotalOutputTokens = 9000
+
+	r := sessionRates(data, burn.Rates{})
+	if r.CostPerHour != 3.0 {
+		t.Errorf("CostPerHour = %f, want 3.0", r.CostPerHour)
+	}
+	if r.OutputPerMinute != 300 {
+		t.Errorf("OutputPerMinute = %f, want 300", r.OutputPerMinute)
+	}
+}
+
+// TestSessionRates_PreferSamples 有近期速率时不使用会话平均
+func TestSessionRates_PreferSamples(t *testing.T) {
+	data := newTestSessionData()
+	recent := burn.Rates{CostPerHour: 9, HasRates: true}
+	if r := sessionRates(data, recent); r.CostPerHour != 9 {
+		t.Errorf("CostPerHour = %f, want 9 from samples", r.CostPerHour)
+	}
+}
+
+// TestSessionRates_ShortSession 会话不足 1 分钟时不计算速率
+func TestSessionRates_ShortSession(t *testing.T) {
+	data := newTestSessionData()
+	data.Cost.TotalDurationMs = 30000
+	if r := sessionRates(data, burn.Rates{}); r.CostPerHour != 0 {
+		t.Errorf("CostPerHour = %f, want 0 for short session", r.CostPerHour)
+	}
+}
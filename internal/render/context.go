@@ -0,0 +1,119 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nyan-statusline-cc/internal/animation"
+	"github.com/nyan-statusline-cc/internal/config"
+	"github.com/nyan-statusline-cc/internal/formatter"
+	"github.com/nyan-statusline-cc/internal/model"
+)
+
+// 上下文段显示模式
+const (
+	contextModePercent   = "percent"   // 彩虹进度条 + 使用百分比
+	contextModeTokens    = "tokens"    // 已用/总量, 如 85k/200k
+	contextModeRemaining = "remaining" // 剩余 token, 如 115k left
+	contextModeCache     = "cache"     // 缓存读取占比, 如 ♻️ 72% cached
+	contextModeCompact   = "compact"   // 距自动压缩的余量, 如 🗜️ 45k to compact
+)
+
+// contextUsage 当前上下文窗口用量明细
+type contextUsage struct {
+	Used      int64 // 输入 + 缓存写入 + 缓存读取
+	CacheRead int64
+	Size      int64
+}
+
+// calcContextUsage 从会话数据汇总上下文用量, 无窗口大小或无用量时返回零值
+func calcContextUsage(data *model.SessionData) contextUsage {
+	if data.ContextWindow.ContextWindowSize <= 0 || data.ContextWindow.CurrentUsage == nil {
+		return contextUsage{}
+	}
+	usage := data.ContextWindow.CurrentUsage
+	return contextUsage{
+		Used:      usage.InputTokens + usage.CacheCreationInputTokens + usage.CacheReadInputTokens,
+		CacheRead: usage.CacheReadInputTokens,
+		Size:      data.ContextWindow.ContextWindowSize,
+	}
+}
+
+// Percent 上下文使用百分比
+func (u contextUsage) Percent() float64 {
+	if u.Size <= 0 {
+		return 0
+	}
+	return float64(u.Used) / float64(u.Size) * 100
+}
+
+// Remaining 剩余可用 token
+func (u contextUsage) Remaining() int64 {
+	return max(u.Size-u.Used, 0)
+}
+
+// CachePercent 缓存读取占已用上下文的百分比
+func (u contextUsage) CachePercent() float64 {
+	if u.Used <= 0 {
+		return 0
+	}
+	return float64(u.CacheRead) / float64(u.Used) * 100
+}
+
+// CompactHeadroom 距自动压缩阈值的剩余 token, 已超过阈值时返回 0
+func (u contextUsage) CompactHeadroom(thresholdPercent float64) int64 {
+	limit := int64(float64(u.Size) * thresholdPercent / 100)
+	return max(limit-u.Used, 0)
+}
+
+// renderContext 按配置的显示模式渲染上下文段
+func renderContext(data *model.SessionData, cfg *config.Config) string {
+	u := calcContextUsage(data)
+	modes := cfg.Context.Modes
+	if len(modes) == 0 {
+		modes = []string{contextModePercent}
+	}
+
+	var items []string
+	for _, mode := range modes {
+		switch mode {
+		case contextModePercent:
+			pct := u.Percent()
+			bar := animation.ProgressBar(pct, barOptions(cfg))
+			items = append(items, fmt.Sprintf("%s %s%.1f%%%s", bar, ContextColor(pct), pct, Reset))
+		case contextModeTokens:
+			if u.Size > 0 {
+				text := formatter.FormatTokens(u.Used) + "/" + formatter.FormatTokens(u.Size)
+				items = append(items, Colorize(text, ContextColor(u.Percent())))
+			}
+		case contextModeRemaining:
+			if u.Size > 0 {
+				items = append(items, Colorize(formatter.FormatTokens(u.Remaining())+" left", Cyan))
+			}
+		case contextModeCache:
+			if u.Used > 0 {
+				items = append(items, Colorize(fmt.Sprintf("♻️ %.0f%% cached", u.CachePercent()), Green))
+			}
+		case contextModeCompact:
+			if u.Size > 0 {
+				items = append(items, compactHeadroomText(u, cfg.Context.AutoCompactPercent))
+			}
+		}
+	}
+	return strings.Join(items, " ")
+}
+
+// compactHeadroomText 渲染距自动压缩的余量, 余量不足 10% 窗口时变红
+func compactHeadroomText(u contextUsage, thresholdPercent float64) string {
+	headroom := u.CompactHeadroom(thresholdPercent)
+	if headroom == 0 {
+		return Colorize("🗜️ compact soon", Red)
+	}
+	color := Green
+	if headroom < u.Size/10 {
+		color = Red
+	} else if headroom < u.Size/4 {
+		color = Yellow
+	}
+	return Colorize("🗜️ "+formatter.FormatTokens(headroom)+" to compact", color)
+}
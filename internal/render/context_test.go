@@ -0,0 +1,66 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nyan-statusline-cc/internal/config"
+)
+
+// TestCalcContextUsage 验证用量明细与派生指标
+func TestCalcContextUsage(t *testing.T) {
+	// 30000 + 5000 + 10000 = 45000 / 200000
+	u := calcContextUsage(newTestSessionData())
+	if u.Used != 45000 || u.Size != 200000 || u.CacheRead != 10000 {
+		t.Fatalf("calcContextUsage() = %+v", u)
+	}
+	if got := u.Remaining(); got != 155000 {
+		t.Errorf("Remaining() = %d, want 155000", got)
+	}
+	if got := u.CachePercent(); got < 22.2 || got > 22.3 {
+		t.Errorf("CachePercent() = %f, want ~22.2", got)
+	}
+	// 80% 阈值 = 160000, 余量 115000
+	if got := u.CompactHeadroom(80); got != 115000 {
+		t.Errorf("CompactHeadroom(80) = %d, want 115000", got)
+	}
+	if got := u.CompactHeadroom(10); got != 0 {
+		t.Errorf("CompactHeadroom beyond threshold = %d, want 0", got)
+	}
+}
+
+// TestCalcContextUsage_NoUsage 无用量时所有指标为 0
+func TestCalcContextUsage_NoUsage(t *testing.T) {
+	data := newTestSessionData()
+	data.ContextWindow.CurrentUsage = nil
+	u := calcContextUsage(data)
+	if u.Percent() != 0 || u.CachePercent() != 0 || u.Remaining() != 0 {
+		t.Errorf("nil usage should yield zero metrics, got %+v", u)
+	}
+}
+
+// TestRenderContext_Modes 验证各显示模式的输出
+func TestRenderContext_Modes(t *testing.T) {
+	cfg := config.Default()
+	cfg.Context.Modes = []string{"tokens", "remaining", "cache", "compact"}
+	got := renderContext(newTestSessionData(), cfg)
+
+	for _, want := range []string{"45k/200k", "155k left", "♻️ 22% cached", "🗜️ 115k to compact"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("renderContext() = %q, should contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "%"+Reset) {
+		t.Errorf("percent mode not configured, got %q", got)
+	}
+}
+
+// TestRenderContext_DefaultPercent 未配置模式时显示进度条 + 百分比
+func TestRenderContext_DefaultPercent(t *testing.T) {
+	cfg := config.Default()
+	cfg.Context.Modes = nil
+	got := renderContext(newTestSessionData(), cfg)
+	if !strings.Contains(got, "22.5%") || !strings.Contains(got, "█") {
+		t.Errorf("default context mode should render bar and percent, got %q", got)
+	}
+}
@@ -0,0 +1,114 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nyan-statusline-cc/internal/formatter"
+	"github.com/nyan-statusline-cc/internal/stats"
+)
+
+// modelNameWidth 模型用量表中名称列的宽度
+const modelNameWidth = 30
+
+// modelMixTop 今日模型占比最多列出的模型数, 其余合并为 "其他"
+const modelMixTop = 3
+
+// modelFamilies 模型系列简称, 按 model.id 子串匹配
+var modelFamilies = []string{"opus", "sonnet", "haiku"}
+
+// modelShortName 模型简称: Claude 模型取系列名 (opus/sonnet/haiku), 其他模型原样返回
+func modelShortName(id string) string {
+	lower := strings.ToLower(id)
+	for _, f := range modelFamilies {
+		if strings.Contains(lower, f) {
+			return f
+		}
+	}
+	return id
+}
+
+// modelMixText 今日模型占比 (按请求数), 如 "🧠 opus 62% · sonnet 38%"; 无请求时返回空串
+func modelMixText(list []stats.ModelStats) string {
+	total := 0
+	byName := make(map[string]int)
+	var names []string
+	for _, m := range list {
+		name := modelShortName(m.Model)
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] += m.Requests
+		total += m.Requests
+	}
+	if total == 0 {
+		return ""
+	}
+	// 稳定排序: 请求数相同时保持成本顺序
+	sort.SliceStable(names, func(i, j int) bool { return byName[names[i]] > byName[names[j]] })
+
+	items := make([]string, 0, modelMixTop+1)
+	rest := total
+	for i, name := range names {
+		if i == modelMixTop {
+			items = append(items, fmt.Sprintf("其他 %.0f%%", float64(rest)/float64(total)*100))
+			break
+		}
+		items = append(items, fmt.Sprintf("%s %.0f%%", name, float64(byName[name])/float64(total)*100))
+		rest -= byName[name]
+	}
+	return "🧠 " + strings.Join(items, " · ")
+}
+
+// ModelsReport 将模型用量渲染为终端表格
+// Parameters:
+//   - list: 按成本降序的模型用量
+//   - period: 统计区间描述 (如 "全部" 或 "2026-01-01 ~ 2026-01-31")
+//
+// Return:
+//   - string: 带 ANSI 颜色的多行表格
+func ModelsReport(list []stats.ModelStats, period string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s\n\n", Colorize(Bold+"🧠 模型用量", Magenta), Colorize(period, Black))
+	if len(list) == 0 {
+		b.WriteString(Colorize("  暂无模型用量", Black) + "\n")
+		return b.String()
+	}
+
+	var total stats.ModelStats
+	for _, m := range list {
+		total.Requests += m.Requests
+		total.Input += m.Input
+		total.Output += m.Output
+		total.CacheWrite += m.CacheWrite
+		total.CacheRead += m.CacheRead
+		total.Cost += m.Cost
+	}
+
+	b.WriteString(Colorize(modelRow("模型", "请求", "输入", "输出", "缓存写", "缓存读", "成本", "占比"), Cyan) + "\n")
+	for _, m := range list {
+		cost, share := "—", "—"
+		if m.Priced {
+			cost = formatter.FormatCost(m.Cost)
+			if total.Cost > 0 {
+				share = fmt.Sprintf("%.0f%%", m.Cost/total.Cost*100)
+			}
+		}
+		b.WriteString(modelRow(m.Model, formatter.FormatCount(int64(m.Requests)),
+			formatter.FormatTokens(m.Input), formatter.FormatTokens(m.Output),
+			formatter.FormatTokens(m.CacheWrite), formatter.FormatTokens(m.CacheRead), cost, share) + "\n")
+	}
+	b.WriteString(Colorize(modelRow("合计", formatter.FormatCount(int64(total.Requests)),
+		formatter.FormatTokens(total.Input), formatter.FormatTokens(total.Output),
+		formatter.FormatTokens(total.CacheWrite), formatter.FormatTokens(total.CacheRead),
+		formatter.FormatCost(total.Cost), ""), Bold) + "\n")
+	return b.String()
+}
+
+// modelRow 按列宽对齐一行模型用量
+func modelRow(name, requests, input, output, cacheWrite, cacheRead, cost, share string) string {
+	return "  " + padRight(name, modelNameWidth) + "  " + padLeft(requests, 7) + "  " +
+		padLeft(input, 7) + "  " + padLeft(output, 7) + "  " + padLeft(cacheWrite, 7) + "  " +
+		padLeft(cacheRead, 7) + "  " + padLeft(cost, 10) + "  " + padLeft(share, 5)
+}
@@ -0,0 +1,61 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nyan-statusline-cc/internal/stats"
+)
+
+func TestModelShortName(t *testing.T) {
+	tests := map[string]string{
+		"claude-opus-4-1-20250805":                   "opus",
+		"us.anthropic.claude-sonnet-4-20250514-v1:0": "sonnet",
+		"claude-3-5-haiku-20241022":                  "haiku",
+		"gpt-oss":                                    "gpt-oss",
+	}
+	for id, want := range tests {
+		if got := modelShortName(id); got != want {
+			t.Errorf("modelShortName(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestModelMixText(t *testing.T) {
+	if got := modelMixText(nil); got != "" {
+		t.Errorf("modelMixText(nil) = %q, want empty", got)
+	}
+	list := []stats.ModelStats{
+		{Model: "claude-opus-4-1", Requests: 2},
+		{Model: "claude-opus-4-5", Requests: 3}, // 同系列合并
+		{Model: "claude-sonnet-4", Requests: 3},
+	}
+	if got := modelMixText(list); got != "🧠 opus 62% · sonnet 38%" {
+		t.Errorf("modelMixText() = %q", got)
+	}
+	list = append(list, stats.ModelStats{Model: "claude-3-haiku", Requests: 1}, stats.ModelStats{Model: "local", Requests: 1})
+	if got := modelMixText(list); !strings.HasSuffix(got, " · 其他 10%") || strings.Count(got, "·") != 3 {
+		t.Errorf("modelMixText(5 models) = %q, want top 3 plus 其他", got)
+	}
+}
+
+func TestModelsReport(t *testing.T) {
+	list := []stats.ModelStats{
+		{Model: "claude-opus-4-1-20250805", Requests: 1, Input: 12000, Output: 3000, Cost: 0.75, Priced: true},
+		{Model: "claude-sonnet-4-20250514", Requests: 2, Input: 10000, Cost: 0.25, Priced: true},
+		{Model: "my-local-model", Requests: 4},
+	}
+	out := ModelsReport(list, "全部")
+	for _, want := range []string{"🧠 模型用量", "全部", "claude-opus-4-1-20250805", "$0.750", "75%", "25%", "my-local-model", "—", "合计", "$1.00"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ModelsReport() missing %q", want)
+		}
+	}
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if VisualWidth(lines[2]) != VisualWidth(lines[3]) {
+		t.Errorf("header width %d != row width %d", VisualWidth(lines[2]), VisualWidth(lines[3]))
+	}
+	if out := ModelsReport(nil, "2026-01-01 ~"); !strings.Contains(out, "暂无模型用量") {
+		t.Errorf("ModelsReport(empty) = %q", out)
+	}
+}
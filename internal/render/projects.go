@@ -0,0 +1,66 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nyan-statusline-cc/internal/formatter"
+	"github.com/nyan-statusline-cc/internal/stats"
+)
+
+// projectNameWidth 项目排行中名称列的宽度
+const projectNameWidth = 24
+
+// projectSortLabels 排序方式的显示名称
+var projectSortLabels = map[string]string{
+	stats.ProjectSortMessages: "消息数",
+	stats.ProjectSortSessions: "会话数",
+	stats.ProjectSortCost:     "成本",
+	stats.ProjectSortRecent:   "最近活跃",
+}
+
+// projectText 当前项目段, 如 "📁 本项目 37会话 · 1,204消息 · $54.20 · ⚡3连"
+func projectText(p *stats.ProjectStats) string {
+	text := "📁 本项目 " + formatter.FormatCount(int64(p.Sessions)) + "会话 · " +
+		formatter.FormatCount(int64(p.Messages)) + "消息 · " + formatter.FormatCost(p.Cost)
+	if p.Streak > 0 {
+		text += " · ⚡" + formatter.FormatCount(int64(p.Streak)) + "连"
+	}
+	return text
+}
+
+// ProjectsReport 将项目排行渲染为终端文本
+// Parameters:
+//   - list: 已排序的项目统计
+//   - sortBy: 排序方式 (用于标题)
+//
+// Return:
+//   - string: 带 ANSI 颜色的多行排行
+func ProjectsReport(list []stats.ProjectStats, sortBy string) string {
+	var b strings.Builder
+	label, ok := projectSortLabels[sortBy]
+	if !ok {
+		label = projectSortLabels[stats.ProjectSortMessages]
+	}
+	fmt.Fprintf(&b, "%s  %s\n\n", Colorize(Bold+"📁 项目排行", Magenta), Colorize("按"+label, Black))
+	if len(list) == 0 {
+		b.WriteString(Colorize("  暂无会话记录", Black) + "\n")
+		return b.String()
+	}
+
+	b.WriteString(Colorize(projectRow("#", "项目", "会话", "消息", "成本", "活跃", "连续", "最后活跃"), Cyan) + "\n")
+	for i, p := range list {
+		b.WriteString(projectRow(fmt.Sprint(i+1), p.Name(),
+			formatter.FormatCount(int64(p.Sessions)), formatter.FormatCount(int64(p.Messages)),
+			formatter.FormatCost(p.Cost), formatter.FormatCount(int64(p.ActiveDays))+"天",
+			formatter.FormatCount(int64(p.Streak)), p.LastActive) + "\n")
+	}
+	return b.String()
+}
+
+// projectRow 按列宽对齐一行项目排行
+func projectRow(rank, name, sessions, messages, cost, active, streak, last string) string {
+	return "  " + padLeft(rank, 3) + "  " + padRight(name, projectNameWidth) + "  " +
+		padLeft(sessions, 6) + "  " + padLeft(messages, 8) + "  " + padLeft(cost, 10) + "  " +
+		padLeft(active, 6) + "  " + padLeft(streak, 4) + "  " + padRight(last, 10)
+}
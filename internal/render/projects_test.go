@@ -0,0 +1,40 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nyan-statusline-cc/internal/stats"
+)
+
+func TestProjectText(t *testing.T) {
+	p := &stats.ProjectStats{Sessions: 37, Messages: 1204, Cost: 54.2}
+	if got := projectText(p); got != "📁 本项目 37会话 · 1204消息 · $54.20" {
+		t.Errorf("projectText() = %q", got)
+	}
+	p.Streak = 3
+	if got := projectText(p); !strings.HasSuffix(got, " · ⚡3连") {
+		t.Errorf("projectText(streak) = %q, want streak suffix", got)
+	}
+}
+
+func TestProjectsReport(t *testing.T) {
+	list := []stats.ProjectStats{
+		{Key: "-home-me-repo-a", Dir: "/home/me/repo-a", Sessions: 37, Messages: 1204, Cost: 54.2, ActiveDays: 20, Streak: 3, LastActive: "2026-02-26"},
+		{Key: "-tmp-x", Messages: 5, LastActive: "2026-01-02"},
+	}
+	out := ProjectsReport(list, stats.ProjectSortCost)
+	for _, want := range []string{"📁 项目排行", "按成本", "repo-a", "$54.20", "20天", "-tmp-x", "2026-01-02"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ProjectsReport() missing %q", want)
+		}
+	}
+	// 数据行与表头同宽对齐
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if VisualWidth(lines[2]) != VisualWidth(lines[3]) {
+		t.Errorf("header width %d != row width %d", VisualWidth(lines[2]), VisualWidth(lines[3]))
+	}
+	if out := ProjectsReport(nil, "bogus"); !strings.Contains(out, "按消息数") || !strings.Contains(out, "暂无会话记录") {
+		t.Errorf("ProjectsReport(empty) = %q", out)
+	}
+}
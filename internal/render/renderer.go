@@ -2,40 +2,69 @@ package render
 
 import (
 	"fmt"
-	"os"
+	"math"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/nyan-statusline-cc/internal/achievement"
 	"github.com/nyan-statusline-cc/internal/animation"
 	"github.com/nyan-statusline-cc/internal/config"
 	"github.com/nyan-statusline-cc/internal/formatter"
 	"github.com/nyan-statusline-cc/internal/git"
+	"github.com/nyan-statusline-cc/internal/ledger"
 	"github.com/nyan-statusline-cc/internal/model"
+	"github.com/nyan-statusline-cc/internal/paths"
+	"github.com/nyan-statusline-cc/internal/pricing"
 	"github.com/nyan-statusline-cc/internal/state"
 	"github.com/nyan-statusline-cc/internal/stats"
 )
 
+// LoadConfig 读取配置并应用其中的进程级设置: data_dir、显示格式、统计日起始小时和连续活跃规则
+// 状态栏渲染与各子命令都经由此处加载配置, 新增设置只需在这里应用一次
+// Parameters:
+//   - p: 数据目录, 按配置中的 data_dir 更新
+//
+// Return:
+//   - *config.Config: 配置
+func LoadConfig(p *paths.Paths) *config.Config {
+	cfg := config.Load(p.DataDir)
+	p.ApplyConfig(cfg.DataDir)
+	formatter.SetLocale(cfg.Format.Locale())
+	stats.SetDayStart(cfg.DayStartHour)
+	stats.SetStreakPolicy(stats.StreakPolicy{
+		WorkdaysOnly:    cfg.Streak.WorkdaysOnly,
+		FreezesPerMonth: cfg.Streak.FreezesPerMonth,
+		MinMessages:     cfg.Streak.MinMessages,
+	})
+	return cfg
+}
+
 // Render 将会话数据渲染为状态栏输出字符串
 // Parameters:
 //   - data: Claude Code 会话数据
+//   - p: 数据目录 (配置、状态文件、统计缓存)
 //
 // Return:
 //   - string: 完整的状态栏输出 (可能包含多行)
-func Render(data *model.SessionData) string {
+func Render(data *model.SessionData, p paths.Paths) string {
 	sep := Colorize(" │ ", Black)
 
-	// 加载配置
-	var cfg *config.Config
-	if execPath, err := os.Executable(); err == nil {
-		cfg = config.Load(filepath.Dir(execPath))
-	} else {
-		cfg = config.Default()
+	cfg := LoadConfig(&p)
+
+	// 会话记录索引每次渲染只更新一次, 成本估算与第二行的统计、项目和模型段共用
+	var idx *stats.Index
+	if cfg.Line2Enabled || needsEstimate(data, cfg) {
+		idx, _ = stats.UpdateIndex(p.ClaudeDir, p.DataDir)
 	}
 
-	line1 := renderLine1(data, sep, cfg)
+	line1 := renderLine1(data, sep, cfg, idx, p)
+
+	// 花费账本: 无论是否显示第二行都持续记账, 保证跨会话汇总完整
+	lg, _ := ledger.Update(p.DataDir, data.SessionID, data.Cost.TotalCostUSD, time.Now().In(cfg.Location()))
 
 	if cfg.Line2Enabled {
-		if line2 := renderLine2(sep, cfg); line2 != "" {
+		if line2 := renderLine2(data, sep, cfg, lg, idx, p); line2 != "" {
 			return line1 + "\n" + line2
 		}
 	}
@@ -43,7 +72,7 @@ func Render(data *model.SessionData) string {
 }
 
 // renderLine1 渲染第一行: 模型、目录、Git、进度条、成本、变更、时长、Token、Nyan Cat、心跳
-func renderLine1(data *model.SessionData, sep string, cfg *config.Config) string {
+func renderLine1(data *model.SessionData, sep string, cfg *config.Config, idx *stats.Index, p paths.Paths) string {
 	var parts []string
 
 	// 模型名称
@@ -76,17 +105,24 @@ func renderLine1(data *model.SessionData, sep string, cfg *config.Config) string
 		}
 	}
 
-	// 上下文使用率 + 彩虹进度条
+	// 上下文使用率 + 彩虹进度条 (及 token 明细/缓存占比/压缩余量)
 	if cfg.IsLine1Enabled("context") {
-		ctxPercent := calcContextPercent(data)
-		bar := animation.RainbowProgressBar(ctxPercent, 10)
-		ctxColor := ContextColor(ctxPercent)
-		parts = append(parts, fmt.Sprintf("%s %s%.1f%%%s", bar, ctxColor, ctxPercent, Reset))
+		if ctx := renderContext(data, cfg); ctx != "" {
+			parts = append(parts, ctx)
+		}
 	}
 
-	// 成本
-	if cfg.IsLine1Enabled("cost") && data.Cost.TotalCostUSD > 0 {
-		parts = append(parts, Colorize("💰 "+formatter.FormatCost(data.Cost.TotalCostUSD), Yellow))
+	// 成本: 无实际成本 (如订阅计划) 时按价格表估算 API 等价成本
+	if cfg.IsLine1Enabled("cost") {
+		if data.Cost.TotalCostUSD > 0 {
+			parts = append(parts, Colorize("💰 "+formatter.FormatCost(data.Cost.TotalCostUSD), Yellow))
+		} else if est, complete := estimateCost(data, cfg, idx); est > 0 {
+			text := "💰 ≈" + formatter.FormatCost(est)
+			if !complete {
+				text += " (不含缓存)"
+			}
+			parts = append(parts, Colorize(text, Yellow))
+		}
 	}
 
 	// 代码变更
@@ -113,103 +149,236 @@ func renderLine1(data *model.SessionData, sep string, cfg *config.Config) string
 		parts = append(parts, Colorize(fmt.Sprintf("📥%s 📤%s", in, out), Cyan))
 	}
 
+	// 滚动用量窗口
+	if cfg.IsLine1Enabled("block") {
+		if block := renderBlock(cfg, p, time.Now()); block != "" {
+			parts = append(parts, block)
+		}
+	}
+
+	// 消耗速率
+	parts = append(parts, renderBurn(data, cfg, p.DataDir, time.Now())...)
+
 	// Nyan Cat 动画 + 处理状态指示器
 	if cfg.IsLine1Enabled("nyan") {
-		nyan := animation.NyanFrame()
-		if indicator := processingIndicator(); indicator != "" {
-			nyan += indicator
-		}
-		parts = append(parts, nyan)
+		parts = append(parts, nyanSegment(time.Now(), calcContextPercent(data), cfg, p.DataDir))
 	}
 
-	// 心跳动画
-	if cfg.IsLine1Enabled("heartbeat") {
-		parts = append(parts, Colorize(animation.Heartbeat(), Red))
+	// 心跳动画 (纯装饰, 关闭动画时隐藏)
+	if cfg.IsLine1Enabled("heartbeat") && cfg.Motion.Level != string(animation.MotionOff) {
+		m := motionFor(cfg, "heartbeat")
+		heartbeat := animation.HeartbeatWith(m)
+		if sp := loadSprite(p.DataDir, cfg.Sprites["heartbeat"]); sp != nil {
+			heartbeat = animation.SpriteFrame(*sp, m)
+		}
+		parts = append(parts, Colorize(heartbeat, Red))
 	}
 
 	termWidth := GetTerminalWidth()
 	return wrapParts(parts, sep, termWidth)
 }
 
-// renderLine2 渲染第二行: 统计信息
-func renderLine2(sep string, cfg *config.Config) string {
-	execPath, err := os.Executable()
-	if err != nil {
-		return ""
-	}
-	binaryDir := filepath.Dir(execPath)
-
-	info, err := stats.GetStatsInfo(binaryDir)
-	if err != nil || info == nil {
-		return ""
+// renderLine2 渲染第二行: 统计信息和花费账本
+func renderLine2(data *model.SessionData, sep string, cfg *config.Config, lg *ledger.Ledger, idx *stats.Index, p paths.Paths) string {
+	// 统计缓存不存在时仍显示花费账本
+	now := time.Now().In(cfg.Location())
+	info, err := stats.GetStatsInfo(p.ClaudeDir, idx, now)
+	hasStats := err == nil && info != nil
+	if !hasStats {
+		info = &model.StatsInfo{}
 	}
 
 	var parts []string
 
 	if cfg.IsLine2Enabled("codingDays") && info.CodingDays > 0 {
-		parts = append(parts, Colorize(fmt.Sprintf("📅 %d天", info.CodingDays), Magenta))
+		parts = append(parts, Colorize("📅 "+formatter.FormatCount(int64(info.CodingDays))+"天", Magenta))
 	}
 	if cfg.IsLine2Enabled("activeDays") && info.ActiveDays > 0 {
-		parts = append(parts, Colorize(fmt.Sprintf("🔥 %d天", info.ActiveDays), Green))
+		parts = append(parts, Colorize("🔥 "+formatter.FormatCount(int64(info.ActiveDays))+"天", Green))
 	}
 	if cfg.IsLine2Enabled("streak") && info.Streak > 0 {
-		parts = append(parts, Colorize(fmt.Sprintf("⚡ %d连", info.Streak), Yellow))
+		parts = append(parts, Colorize(streakText(info, cfg.Streak), Yellow))
 	}
 	if cfg.IsLine2Enabled("sessions") && info.TotalSessions > 0 {
-		parts = append(parts, Colorize(fmt.Sprintf("💬 %d会话", info.TotalSessions), Blue))
+		parts = append(parts, Colorize("💬 "+formatter.FormatCount(int64(info.TotalSessions))+"会话", Blue))
 	}
 	if cfg.IsLine2Enabled("messages") && info.TotalMessages > 0 {
-		parts = append(parts, Colorize(fmt.Sprintf("🗣️ %d消息", info.TotalMessages), Cyan))
+		parts = append(parts, Colorize("🗣️ "+formatter.FormatCount(int64(info.TotalMessages))+"消息", Cyan))
+	}
+	if cfg.IsLine2Enabled("recentActive") && hasStats {
+		parts = append(parts, Colorize(recentActiveText(info), Green))
 	}
 	if cfg.IsLine2Enabled("todayMessages") && info.TodayMessages > 0 {
-		parts = append(parts, Colorize(fmt.Sprintf("📈 今日%d", info.TodayMessages), Cyan))
+		parts = append(parts, Colorize("📈 今日"+formatter.FormatCount(int64(info.TodayMessages)), Cyan)+todayDelta(info))
+	}
+	if cfg.IsLine2Enabled("sparkline") && hasStats {
+		if spark := sparklineSegment(info.DailyMessages, cfg.Sparkline.Days); spark != "" {
+			parts = append(parts, spark)
+		}
+	}
+	if cfg.IsLine2Enabled("records") && info.Records.LongestStreak > 0 {
+		parts = append(parts, Colorize(recordsText(info.Records), Yellow))
+	}
+	if cfg.IsLine2Enabled("newRecord") && len(info.NewRecords) > 0 {
+		parts = append(parts, animation.RecordFlash(motionFor(cfg, "newRecord")))
+	}
+	if cfg.IsLine2Enabled("spend") && lg != nil {
+		if spend := spendSegment(lg.Totals(now), cfg.Budget); spend != "" {
+			parts = append(parts, spend)
+		}
 	}
 	if cfg.IsLine2Enabled("peakHour") && info.HasPeakHour {
 		emoji := peakHourEmoji(info.PeakHour)
 		parts = append(parts, Colorize(fmt.Sprintf("%s %d点", emoji, info.PeakHour), Blue))
 	}
+	if cfg.IsLine2Enabled("heatmap") && info.HasPeakHour {
+		parts = append(parts, "🌡️ "+animation.HourHeatmap(info.HourCounts))
+	}
 
-	// 成就
+	if cfg.IsLine2Enabled("project") {
+		if projects := idx.ProjectList(now, cfg.Prices); len(projects) > 0 {
+			if proj := stats.FindProject(projects, data.Workspace.CurrentDir); proj != nil {
+				parts = append(parts, Colorize(projectText(proj), Blue))
+			}
+		}
+	}
+
+	if cfg.IsLine2Enabled("modelMix") {
+		today := stats.Today(now)
+		if models := idx.ModelList(today, today, now.Location(), cfg.Prices); len(models) > 0 {
+			if mix := modelMixText(models); mix != "" {
+				parts = append(parts, Colorize(mix, Magenta))
+			}
+		}
+	}
+
+	// 成就: 无论是否显示都评估解锁, 保证解锁日期准确
+	metrics := achievement.MetricsFrom(info, data)
+	unlocked, _ := achievement.Evaluate(p.DataDir, metrics, now)
 	if cfg.IsLine2Enabled("achievement") {
-		if achievement := getAchievement(info); achievement != "" {
-			parts = append(parts, Colorize(achievement, Yellow))
+		rotate := time.Duration(cfg.Achievements.RotateSec) * time.Second
+		if badge := achievement.Pick(unlocked, cfg.Achievements.Display, time.Now(), rotate); badge != nil {
+			parts = append(parts, Colorize(badge.Text(), Yellow))
+		}
+	}
+	if cfg.IsLine2Enabled("achievementProgress") && hasStats {
+		if progress := progressSegment(unlocked, metrics, cfg.Achievements.Progress); progress != "" {
+			parts = append(parts, progress)
 		}
 	}
 
-	// 随机状态
-	if cfg.IsLine2Enabled("randomStatus") {
-		parts = append(parts, Colorize(animation.RandomStatus(), Cyan))
+	// 随机状态: 仅作点缀, 无其他内容时不单独显示
+	if cfg.IsLine2Enabled("randomStatus") && (hasStats || len(parts) > 0) {
+		parts = append(parts, Colorize(animation.RandomStatusWith(motionFor(cfg, "randomStatus")), Cyan))
+	}
+
+	// 默认开启的段较多, 与第一行一样按终端宽度自动换行
+	return wrapParts(parts, sep, GetTerminalWidth())
+}
+
+// nyanSegment 读取 hook 写入的状态文件, 返回 Nyan Cat 动画 + 处理状态指示器
+// 处理中显示 "⏳"; 刚完成时播放庆祝动画, 结束后回到 "⌛💯" 静止帧
+// 关闭动画时只显示处理状态指示器
+func nyanSegment(now time.Time, ctxPercent float64, cfg *config.Config, dataDir string) string {
+	s := state.Load(dataDir)
+	opts := nyanOptions(s, ctxPercent, cfg.Mood, now)
+	opts.Sprite = loadSprite(dataDir, cfg.Sprites["nyan"])
+	opts.Motion = motionFor(cfg, "nyan")
+
+	frame := animation.NyanFrameWith(opts)
+	if cfg.Motion.Level == string(animation.MotionOff) {
+		frame = ""
+	}
+
+	// 无状态文件, 默认处理中
+	if s == nil || s.Status != state.StatusCompleted {
+		return frame + "⏳"
+	}
+	if opts.Motion.Animated() && !s.ChangedAt.IsZero() {
+		if frame := animation.Celebration(now.Sub(s.ChangedAt), s.Record); frame != "" {
+			return frame + "💯"
+		}
 	}
+	return frame + "⌛💯"
+}
 
-	if len(parts) == 0 {
-		return ""
+// barOptions 根据配置生成上下文进度条参数
+func barOptions(cfg *config.Config) animation.BarOptions {
+	opts := animation.BarOptions{
+		Width:     cfg.Progress.Width,
+		Style:     animation.BarStyle(cfg.Progress.Style),
+		TrueColor: cfg.Progress.TrueColor,
+		Motion:    motionFor(cfg, "progress"),
+	}
+	if cfg.Progress.CompactMarker {
+		opts.Marker = cfg.Context.AutoCompactPercent
+	}
+	return opts
+}
+
+// motionFor 返回指定动画的运动参数: 全局级别 + 速度倍率, 速度为 0 的动画使用静止帧
+func motionFor(cfg *config.Config, key string) animation.Motion {
+	m := animation.Motion{Level: animation.MotionLevel(cfg.Motion.Level)}
+	if speed, ok := cfg.Motion.Speed[key]; ok {
+		if speed <= 0 && m.Animated() {
+			m.Level = animation.MotionReduced
+		}
+		m.Speed = speed
 	}
-	return strings.Join(parts, sep)
+	return m
 }
 
-// processingIndicator 读取 hook 写入的状态文件, 返回处理状态指示器
-// 处理中返回 "⏳", 处理完成返回 "⌛💯"
-func processingIndicator() string {
-	execPath, err := os.Executable()
-	if err != nil {
-		return ""
+// nyanOptions 根据处理状态和上下文使用率决定猫咪心情
+// 优先级: 上下文告急 > 处理中 > 长时间空闲 > 处理完成
+func nyanOptions(s *state.Info, ctxPercent float64, moodCfg config.MoodConfig, now time.Time) animation.NyanOptions {
+	if !moodCfg.Enabled {
+		return animation.NyanOptions{}
 	}
-	binaryDir := filepath.Dir(execPath)
 
-	if state.IsProcessing(binaryDir) {
-		return "⏳"
+	// 上下文告急: 尾巴随剩余空间缩短并闪烁
+	if moodCfg.SweatPercent > 0 && moodCfg.SweatPercent < 100 && ctxPercent >= moodCfg.SweatPercent {
+		remaining := (100 - ctxPercent) / (100 - moodCfg.SweatPercent)
+		tailLen := max(int(math.Ceil(remaining*7)), 1)
+		return animation.NyanOptions{Mood: animation.MoodSweating, TailLen: tailLen, Flicker: true}
 	}
-	return "⌛💯"
+
+	if s == nil || s.Status != state.StatusCompleted {
+		return animation.NyanOptions{Mood: animation.MoodFocused}
+	}
+	idle := time.Duration(moodCfg.SleepyAfterMin) * time.Minute
+	if moodCfg.SleepyAfterMin > 0 && !s.ChangedAt.IsZero() && now.Sub(s.ChangedAt) >= idle {
+		return animation.NyanOptions{Mood: animation.MoodSleepy}
+	}
+	return animation.NyanOptions{Mood: animation.MoodCelebrating}
+}
+
+// needsEstimate 第一行是否需要估算成本 (无实际成本时)
+func needsEstimate(data *model.SessionData, cfg *config.Config) bool {
+	return cfg.IsLine1Enabled("cost") && data.Cost.TotalCostUSD <= 0
+}
+
+// estimateCost 按模型价格估算会话的 API 等价成本, 未知模型返回 0
+// 会话记录已被索引时按其中各模型的输入/输出/缓存写入/缓存读取 token 计价, complete 为 true;
+// 否则只能用会话数据中的累计输入/输出 token, 缓存不计入, complete 为 false
+func estimateCost(data *model.SessionData, cfg *config.Config, idx *stats.Index) (cost float64, complete bool) {
+	if idx != nil && data.TranscriptPath != "" {
+		if models := idx.TranscriptModels(data.TranscriptPath, cfg.Prices); len(models) > 0 {
+			for _, m := range models {
+				cost += m.Cost
+			}
+			return cost, true
+		}
+	}
+	price, ok := pricing.Lookup(data.Model.ID, cfg.Prices)
+	if !ok {
+		return 0, false
+	}
+	return price.Cost(data.ContextWindow.TotalInputTokens, data.ContextWindow.TotalOutputTokens, 0, 0), false
 }
 
 // calcContextPercent 计算上下文使用百分比
 func calcContextPercent(data *model.SessionData) float64 {
-	if data.ContextWindow.ContextWindowSize <= 0 || data.ContextWindow.CurrentUsage == nil {
-		return 0
-	}
-	usage := data.ContextWindow.CurrentUsage
-	total := usage.InputTokens + usage.CacheCreationInputTokens + usage.CacheReadInputTokens
-	return float64(total) / float64(data.ContextWindow.ContextWindowSize) * 100
+	return calcContextUsage(data).Percent()
 }
 
 // peakHourEmoji 根据小时返回时段 emoji
@@ -225,33 +394,3 @@ func peakHourEmoji(hour int) string {
 		return "🌅"
 	}
 }
-
-// getAchievement 根据统计数据返回成就徽章
-func getAchievement(info *model.StatsInfo) string {
-	switch {
-	case info.TotalMessages >= 1000:
-		return "🏆 千言万语"
-	case info.TotalMessages >= 500:
-		return "🥇 消息达人"
-	case info.TotalMessages >= 100:
-		return "🥈 话唠新星"
-	}
-	switch {
-	case info.TotalSessions >= 100:
-		return "👑 会话之王"
-	case info.TotalSessions >= 50:
-		return "⭐ 会话专家"
-	}
-	switch {
-	case info.Streak >= 30:
-		return "🔥 月度坚持"
-	case info.Streak >= 7:
-		return "💪 周度坚持"
-	case info.Streak >= 3:
-		return "✊ 三连击"
-	}
-	if info.ActiveDays >= 30 {
-		return "🎖️ 老用户"
-	}
-	return ""
-}
@@ -1,12 +1,30 @@
 package render
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/nyan-statusline-cc/internal/animation"
+	"github.com/nyan-statusline-cc/internal/config"
+	"github.com/nyan-statusline-cc/internal/formatter"
+	"github.com/nyan-statusline-cc/internal/ledger"
 	"github.com/nyan-statusline-cc/internal/model"
+	"github.com/nyan-statusline-cc/internal/paths"
+	"github.com/nyan-statusline-cc/internal/pricing"
+	"github.com/nyan-statusline-cc/internal/state"
+	"github.com/nyan-statusline-cc/internal/stats"
 )
 
+// testPaths 返回指向临时目录的数据目录, 避免测试读写真实数据
+func testPaths(t *testing.T) paths.Paths {
+	t.Helper()
+	dir := t.TempDir()
+	return paths.Paths{ClaudeDir: dir, DataDir: dir}
+}
+
 // newTestSessionData 构造测试用的 SessionData
 func newTestSessionData() *model.SessionData {
 	return &model.SessionData{
@@ -38,7 +56,7 @@ func newTestSessionData() *model.SessionData {
 // TestRender_NotEmpty 验证 Render 输出非空
 func TestRender_NotEmpty(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if result == "" {
 		t.Error("Render() should not return empty string")
 	}
@@ -47,7 +65,7 @@ func TestRender_NotEmpty(t *testing.T) {
 // TestRender_ContainsSeparator 验证输出包含分隔符
 func TestRender_ContainsSeparator(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if !strings.Contains(result, "│") {
 		t.Error("Render output should contain separator '│'")
 	}
@@ -56,7 +74,7 @@ func TestRender_ContainsSeparator(t *testing.T) {
 // TestRender_ContainsModelEmoji 验证输出包含模型 emoji
 func TestRender_ContainsModelEmoji(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if !strings.Contains(result, "👾") {
 		t.Error("Render output should contain model emoji '👾'")
 	}
@@ -65,7 +83,7 @@ func TestRender_ContainsModelEmoji(t *testing.T) {
 // TestRender_ContainsModelName 验证输出包含模型名称
 func TestRender_ContainsModelName(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if !strings.Contains(result, "claude-opus-4") {
 		t.Error("Render output should contain model name")
 	}
@@ -74,7 +92,7 @@ func TestRender_ContainsModelName(t *testing.T) {
 // TestRender_ContainsDirEmoji 验证输出包含目录 emoji
 func TestRender_ContainsDirEmoji(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if !strings.Contains(result, "🗂️") {
 		t.Error("Render output should contain directory emoji '🗂️'")
 	}
@@ -83,7 +101,7 @@ func TestRender_ContainsDirEmoji(t *testing.T) {
 // TestRender_ContainsDirName 验证输出包含目录名 (basename)
 func TestRender_ContainsDirName(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if !strings.Contains(result, "project") {
 		t.Error("Render output should contain directory basename 'project'")
 	}
@@ -92,7 +110,7 @@ func TestRender_ContainsDirName(t *testing.T) {
 // TestRender_ContainsCost 验证输出包含成本信息
 func TestRender_ContainsCost(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if !strings.Contains(result, "💰") {
 		t.Error("Render output should contain cost emoji '💰'")
 	}
@@ -104,7 +122,7 @@ func TestRender_ContainsCost(t *testing.T) {
 // TestRender_ContainsCodeChanges 验证输出包含代码变更
 func TestRender_ContainsCodeChanges(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if !strings.Contains(result, "+42") {
 		t.Error("Render output should contain added lines '+42'")
 	}
@@ -116,7 +134,7 @@ func TestRender_ContainsCodeChanges(t *testing.T) {
 // TestRender_ContainsProgressBar 验证输出包含进度条 (彩虹色填充块)
 func TestRender_ContainsProgressBar(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	// 进度条使用 "█" 填充
 	if !strings.Contains(result, "█") {
 		t.Error("Render output should contain progress bar filled blocks '█'")
@@ -126,7 +144,7 @@ func TestRender_ContainsProgressBar(t *testing.T) {
 // TestRender_ContainsContextPercent 验证输出包含上下文使用百分比
 func TestRender_ContainsContextPercent(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if !strings.Contains(result, "%") {
 		t.Error("Render output should contain context usage percentage")
 	}
@@ -135,7 +153,7 @@ func TestRender_ContainsContextPercent(t *testing.T) {
 // TestRender_ContainsTokenStats 验证输出包含 Token 统计
 func TestRender_ContainsTokenStats(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if !strings.Contains(result, "📥") {
 		t.Error("Render output should contain input token emoji '📥'")
 	}
@@ -147,7 +165,7 @@ func TestRender_ContainsTokenStats(t *testing.T) {
 // TestRender_ContainsDuration 验证输出包含会话时长
 func TestRender_ContainsDuration(t *testing.T) {
 	data := newTestSessionData()
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if !strings.Contains(result, "⏱️") {
 		t.Error("Render output should contain duration emoji '⏱️'")
 	}
@@ -157,7 +175,7 @@ func TestRender_ContainsDuration(t *testing.T) {
 func TestRender_DefaultModelName(t *testing.T) {
 	data := newTestSessionData()
 	data.Model.DisplayName = ""
-	result := Render(data)
+	result := Render(data, testPaths(t))
 	if !strings.Contains(result, "Unknown") {
 		t.Error("Render output should contain 'Unknown' when model name is empty")
 	}
@@ -222,30 +240,207 @@ func TestPeakHourEmoji(t *testing.T) {
 	}
 }
 
-// TestGetAchievement 验证成就徽章逻辑
-func TestGetAchievement(t *testing.T) {
+// TestNyanOptions 验证猫咪心情规则
+func TestNyanOptions(t *testing.T) {
+	now := time.Date(2026, 2, 26, 10, 0, 0, 0, time.UTC)
+	moodCfg := config.MoodConfig{Enabled: true, SleepyAfterMin: 30, SweatPercent: 80}
+	processing := &state.Info{Status: state.StatusProcessing, ChangedAt: now.Add(-time.Minute)}
+	justDone := &state.Info{Status: state.StatusCompleted, ChangedAt: now.Add(-time.Minute)}
+	longIdle := &state.Info{Status: state.StatusCompleted, ChangedAt: now.Add(-time.Hour)}
+
 	tests := []struct {
-		name string
-		info *model.StatsInfo
-		want string
+		name    string
+		s       *state.Info
+		percent float64
+		want    animation.Mood
 	}{
-		{"1000 messages", &model.StatsInfo{TotalMessages: 1000}, "🏆 千言万语"},
-		{"500 messages", &model.StatsInfo{TotalMessages: 500}, "🥇 消息达人"},
-		{"100 messages", &model.StatsInfo{TotalMessages: 100}, "🥈 话唠新星"},
-		{"100 sessions", &model.StatsInfo{TotalSessions: 100}, "👑 会话之王"},
-		{"50 sessions", &model.StatsInfo{TotalSessions: 50}, "⭐ 会话专家"},
-		{"30 streak", &model.StatsInfo{Streak: 30}, "🔥 月度坚持"},
-		{"7 streak", &model.StatsInfo{Streak: 7}, "💪 周度坚持"},
-		{"3 streak", &model.StatsInfo{Streak: 3}, "✊ 三连击"},
-		{"30 active days", &model.StatsInfo{ActiveDays: 30}, "🎖️ 老用户"},
-		{"no achievement", &model.StatsInfo{}, ""},
+		{"no state", nil, 10, animation.MoodFocused},
+		{"processing", processing, 10, animation.MoodFocused},
+		{"just completed", justDone, 10, animation.MoodCelebrating},
+		{"long idle", longIdle, 10, animation.MoodSleepy},
+		{"context nearly full", longIdle, 90, animation.MoodSweating},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getAchievement(tt.info)
-			if got != tt.want {
-				t.Errorf("getAchievement() = %q, want %q", got, tt.want)
+			got := nyanOptions(tt.s, tt.percent, moodCfg, now)
+			if got.Mood != tt.want {
+				t.Errorf("Mood = %q, want %q", got.Mood, tt.want)
 			}
 		})
 	}
 }
+
+// TestNyanOptions_SweatTail 上下文越满尾巴越短, 至少保留 1 格
+func TestNyanOptions_SweatTail(t *testing.T) {
+	now := time.Date(2026, 2, 26, 10, 0, 0, 0, time.UTC)
+	moodCfg := config.MoodConfig{Enabled: true, SweatPercent: 80}
+
+	if got := nyanOptions(nil, 80, moodCfg, now); got.TailLen != 7 || !got.Flicker {
+		t.Errorf("at threshold: TailLen=%d Flicker=%v, want 7 true", got.TailLen, got.Flicker)
+	}
+	if got := nyanOptions(nil, 90, moodCfg, now); got.TailLen != 4 {
+		t.Errorf("90%%: TailLen = %d, want 4", got.TailLen)
+	}
+	if got := nyanOptions(nil, 100, moodCfg, now); got.TailLen != 1 {
+		t.Errorf("100%%: TailLen = %d, want 1", got.TailLen)
+	}
+}
+
+// TestNyanOptions_Disabled 关闭心情后使用默认帧
+func TestNyanOptions_Disabled(t *testing.T) {
+	got := nyanOptions(nil, 99, config.MoodConfig{}, time.Now())
+	if got != (animation.NyanOptions{}) {
+		t.Errorf("disabled mood should return zero options, got %+v", got)
+	}
+}
+
+// TestMotionFor 验证全局动画级别与各动画速度倍率的组合
+func TestMotionFor(t *testing.T) {
+	cfg := config.Default()
+	cfg.Motion.Speed["heartbeat"] = 0.5
+
+	if m := motionFor(cfg, "nyan"); !m.Animated() || m.Speed != 0 {
+		t.Errorf("nyan default: %+v, want animated at default speed", m)
+	}
+	if m := motionFor(cfg, "heartbeat"); !m.Animated() || m.Speed != 0.5 {
+		t.Errorf("heartbeat: %+v, want animated at 0.5x", m)
+	}
+	if m := motionFor(cfg, "progress"); m.Animated() {
+		t.Errorf("progress default: %+v, want static", m)
+	}
+
+	cfg.Motion.Level = string(animation.MotionReduced)
+	if m := motionFor(cfg, "heartbeat"); m.Animated() {
+		t.Errorf("reduced heartbeat: %+v, want static", m)
+	}
+}
+
+// TestRender_MotionOff 关闭动画时隐藏心跳
+func TestRender_MotionOff(t *testing.T) {
+	cfg := config.Default()
+	cfg.Motion.Level = string(animation.MotionOff)
+	line := renderLine1(newTestSessionData(), " │ ", cfg, nil, testPaths(t))
+	for _, frame := range []string{"👻", "👹", "💗", "🎃"} {
+		if strings.Contains(line, frame) {
+			t.Errorf("motion off should hide heartbeat %q, got %q", frame, line)
+		}
+	}
+}
+
+// TestBarOptions 仅在开启 compact_marker 时标记自动压缩阈值
+func TestBarOptions(t *testing.T) {
+	cfg := config.Default()
+	if opts := barOptions(cfg); opts.Width != 10 || opts.Marker != 0 {
+		t.Errorf("default barOptions = %+v, want width 10 without marker", opts)
+	}
+	cfg.Progress.CompactMarker = true
+	cfg.Context.AutoCompactPercent = 75
+	if opts := barOptions(cfg); opts.Marker != 75 {
+		t.Errorf("Marker = %v, want 75", opts.Marker)
+	}
+}
+
+// TestEstimateCost 按价格表估算成本, 用户价格覆盖内置价格, 未知模型返回 0
+func TestEstimateCost(t *testing.T) {
+	data := newTestSessionData()
+	data.Model.ID = "claude-sonnet-4-20250514"
+	cfg := config.Default()
+
+	// 50000 * $3/M + 10000 * $15/M = 0.15 + 0.15, 无会话记录时不含缓存
+	if got, complete := estimateCost(data, cfg, nil); got < 0.2999 || got > 0.3001 || complete {
+		t.Errorf("estimateCost() = %f, %v; want 0.30 without cache", got, complete)
+	}
+
+	cfg.Prices = map[string]pricing.Price{"claude-sonnet-4": {Input: 1, Output: 1}}
+	if got, _ := estimateCost(data, cfg, nil); got < 0.0599 || got > 0.0601 {
+		t.Errorf("estimateCost() with override = %f, want 0.06", got)
+	}
+
+	data.Model.ID = "unknown-model"
+	if got, _ := estimateCost(data, cfg, nil); got != 0 {
+		t.Errorf("estimateCost(unknown) = %f, want 0", got)
+	}
+}
+
+// TestEstimateCost_Transcript 会话记录已索引时按各模型计入缓存写入/读取
+func TestEstimateCost_Transcript(t *testing.T) {
+	p := testPaths(t)
+	proj := filepath.Join(p.ClaudeDir, "projects", "-p")
+	if err := os.MkdirAll(proj, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(proj, "s.jsonl")
+	line := `{"type":"assistant","timestamp":"2026-02-26T09:00:00Z","sessionId":"s","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":1000000,"cache_read_input_tokens":1000000}}}
+`
+	if err := os.WriteFile(path, []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+	idx, _ := stats.UpdateIndex(p.ClaudeDir, p.DataDir)
+
+	data := newTestSessionData()
+	data.TranscriptPath = path
+	data.Cost.TotalCostUSD = 0
+	// 1M 输入 $3 + 1M 缓存读取 $0.3
+	if got, complete := estimateCost(data, config.Default(), idx); got < 3.2999 || got > 3.3001 || !complete {
+		t.Errorf("estimateCost() = %f, %v; want 3.30 including cache", got, complete)
+	}
+	out := renderLine1(data, " │ ", config.Default(), idx, p)
+	if !strings.Contains(out, "💰 ≈$3.30") || strings.Contains(out, "不含缓存") {
+		t.Errorf("renderLine1() = %q, want full estimate", out)
+	}
+}
+
+// TestRender_EstimatedCost 无实际成本时显示估算成本, 无会话记录时标注不含缓存
+func TestRender_EstimatedCost(t *testing.T) {
+	data := newTestSessionData()
+	data.Model.ID = "claude-sonnet-4-20250514"
+	data.Cost.TotalCostUSD = 0
+	line := renderLine1(data, " │ ", config.Default(), nil, testPaths(t))
+	if !strings.Contains(line, "💰 ≈$0.300 (不含缓存)") {
+		t.Errorf("renderLine1() should show estimated cost, got %q", line)
+	}
+}
+
+// TestFormatLocale 货币配置应作用于费用类段落
+func TestFormatLocale(t *testing.T) {
+	defer formatter.SetLocale(formatter.DefaultLocale())
+
+	cfg := config.Default()
+	cfg.Format.CurrencySymbol = "¥"
+	cfg.Format.ExchangeRate = 7
+	cfg.Format.ThousandsSep = ","
+	formatter.SetLocale(cfg.Format.Locale())
+
+	got := spendSegment(ledger.Totals{Today: 200}, config.BudgetConfig{})
+	if !strings.Contains(got, "¥1,400.00") {
+		t.Errorf("spendSegment() = %q, want converted ¥1,400.00", got)
+	}
+}
+
+// TestRenderLine2_Wraps 第二行与第一行一样按终端宽度换行
+func TestRenderLine2_Wraps(t *testing.T) {
+	t.Setenv("COLUMNS", "60")
+	if GetTerminalWidth() != 60 {
+		t.Skip("terminal width comes from /dev/tty")
+	}
+	p := testPaths(t)
+	today := time.Now().Format("2006-01-02")
+	cache := `{"firstSessionDate":"2025-01-01T00:00:00Z","totalSessions":900,"totalMessages":12000,` +
+		`"dailyActivity":[{"date":"` + today + `","messageCount":120,"sessionCount":8}],` +
+		`"hourCounts":{"9":5,"14":3}}`
+	if err := os.WriteFile(filepath.Join(p.ClaudeDir, "stats-cache.json"), []byte(cache), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, _ := stats.UpdateIndex(p.ClaudeDir, p.DataDir)
+	line := renderLine2(newTestSessionData(), " │ ", config.Default(), nil, idx, p)
+	rows := strings.Split(line, "\n")
+	if len(rows) < 2 {
+		t.Fatalf("renderLine2() should wrap at 60 columns, got %q", line)
+	}
+	for _, row := range rows {
+		if w := VisualWidth(row); w > 60 {
+			t.Errorf("row width = %d, want <= 60: %q", w, row)
+		}
+	}
+}
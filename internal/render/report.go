@@ -0,0 +1,110 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/animation"
+	"github.com/nyan-statusline-cc/internal/formatter"
+	"github.com/nyan-statusline-cc/internal/stats"
+)
+
+// hourBarWidth 时段分布柱状图的最大宽度
+const hourBarWidth = 30
+
+// weekdayLabels 日历热力图行标签 (周一至周日)
+var weekdayLabels = []string{"一", "二", "三", "四", "五", "六", "日"}
+
+// StatsReport 将统计报告渲染为终端文本
+// Parameters:
+//   - r: 统计报告
+//
+// Return:
+//   - string: 带 ANSI 颜色的多行报告
+func StatsReport(r *stats.Report) string {
+	var b strings.Builder
+	count := func(n int) string { return formatter.FormatCount(int64(n)) }
+
+	fmt.Fprintf(&b, "%s  %s\n\n", Colorize(Bold+"📊 统计报告", Magenta), Colorize(r.Since+" ~ "+r.Until, Black))
+
+	b.WriteString(Colorize(Bold+"总览", Cyan) + "\n")
+	fmt.Fprintf(&b, "  📅 使用 %s 天   💬 %s 会话   🗣️ %s 消息   ⚡ 当前连续 %s 天\n",
+		count(r.CodingDays), count(r.TotalSessions), count(r.TotalMessages), count(r.CurrentStreak))
+	if rec := r.Records; rec.LongestStreak > 0 {
+		fmt.Fprintf(&b, "  🏅 最长连续 %s 天 (%s ~ %s) · 单日最多 %s 消息 (%s) / %s 会话 (%s)\n",
+			count(rec.LongestStreak), rec.LongestStreakStart, rec.LongestStreakEnd,
+			count(rec.MostMessages), rec.MostMessagesDate, count(rec.MostSessions), rec.MostSessionsDate)
+	}
+
+	b.WriteString("\n" + Colorize(Bold+"区间", Cyan) + "\n")
+	sessions := count(r.Sessions) + " 会话"
+	if !r.SessionsDistinct {
+		sessions += " (每日之和)"
+	}
+	fmt.Fprintf(&b, "  🔥 活跃 %s 天   🗣️ %s 消息   💬 %s\n",
+		count(r.ActiveDays), count(r.Messages), sessions)
+	if r.LongestStreak.Days > 0 {
+		fmt.Fprintf(&b, "  🏆 最长连续 %s 天 (%s ~ %s)\n", count(r.LongestStreak.Days), r.LongestStreak.Start, r.LongestStreak.End)
+	}
+	if r.BestDay.Messages > 0 {
+		fmt.Fprintf(&b, "  🌟 最佳单日 %s · %s 消息\n", r.BestDay.Date, count(r.BestDay.Messages))
+	}
+	if r.ActiveDays > 0 {
+		fmt.Fprintf(&b, "  📈 活跃日均 %.1f 消息 · %.1f 会话\n", r.AvgMessagesPerActive, r.AvgSessionsPerActive)
+	}
+	fmt.Fprintf(&b, "  📆 近 7 天 %s 消息 (前 7 天 %s) %s\n\n",
+		count(r.Trend.ThisWeek), count(r.Trend.LastWeek), trendText(r.Trend))
+
+	b.WriteString(Colorize(Bold+"活跃日历", Cyan) + "\n")
+	if len(r.Days) > 0 {
+		values := make([]int, len(r.Days))
+		for i, d := range r.Days {
+			values[i] = d.Messages
+		}
+		start, _ := time.Parse("2006-01-02", r.Days[0].Date)
+		for i, row := range animation.CalendarHeatmap(values, start) {
+			fmt.Fprintf(&b, "  %s %s\n", Colorize(weekdayLabels[i], Black), row)
+		}
+	}
+	b.WriteString("\n")
+
+	b.WriteString(Colorize(Bold+"时段分布", Cyan) + "  " + animation.HourHeatmap(r.HourCounts) + "\n")
+	b.WriteString(hourBars(r.HourCounts))
+	return b.String()
+}
+
+// trendText 环比变化文本: 上升绿色, 下降红色
+func trendText(t stats.WeekTrend) string {
+	switch {
+	case t.LastWeek == 0 && t.ThisWeek > 0:
+		return Colorize("↑ 新增", Green)
+	case t.LastWeek == 0:
+		return Colorize("—", Black)
+	case t.ChangePct >= 0:
+		return Colorize(fmt.Sprintf("↑%.0f%%", t.ChangePct), Green)
+	}
+	return Colorize(fmt.Sprintf("↓%.0f%%", math.Abs(t.ChangePct)), Red)
+}
+
+// hourBars 按小时输出水平柱状图, 无数据时返回空串
+func hourBars(counts [24]int) string {
+	maxV := 0
+	for _, c := range counts {
+		maxV = max(maxV, c)
+	}
+	if maxV == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for h, c := range counts {
+		bar := Colorize("·", Black)
+		if c > 0 {
+			width := max(1, int(math.Round(float64(c)/float64(maxV)*hourBarWidth)))
+			bar = Colorize(strings.Repeat("█", width), Blue) + " " + formatter.FormatCount(int64(c))
+		}
+		fmt.Fprintf(&b, "  %02d %s\n", h, bar)
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nyan-statusline-cc/internal/stats"
+)
+
+func TestStatsReport(t *testing.T) {
+	r := &stats.Report{
+		Since:                "2026-02-20",
+		Until:                "2026-02-26",
+		ActiveDays:           2,
+		Messages:             30,
+		Sessions:             3,
+		LongestStreak:        stats.Streak{Days: 2, Start: "2026-02-25", End: "2026-02-26"},
+		BestDay:              stats.DayCount{Date: "2026-02-26", Messages: 20},
+		AvgMessagesPerActive: 15,
+		Trend:                stats.WeekTrend{ThisWeek: 30, LastWeek: 40, ChangePct: -25},
+		Days: []stats.DayCount{
+			{Date: "2026-02-25", Messages: 10}, {Date: "2026-02-26", Messages: 20},
+		},
+	}
+	r.HourCounts[9] = 3
+
+	got := StatsReport(r)
+	for _, want := range []string{
+		"2026-02-20 ~ 2026-02-26",
+		"最长连续 2 天 (2026-02-25 ~ 2026-02-26)",
+		"最佳单日 2026-02-26 · 20 消息",
+		"活跃日均 15.0 消息",
+		"↓25%",
+		"  09 ",
+		"💬 3 会话 (每日之和)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("StatsReport() missing %q", want)
+		}
+	}
+	// 日历 7 行
+	if n := strings.Count(got, "■"); n != 2 {
+		t.Errorf("calendar has %d active cells, want 2", n)
+	}
+
+	r.SessionsDistinct = true
+	if got := StatsReport(r); !strings.Contains(got, "💬 3 会话\n") {
+		t.Errorf("distinct sessions should not be labelled as a daily sum, got %q", got)
+	}
+}
+
+func TestTrendText(t *testing.T) {
+	cases := []struct {
+		trend stats.WeekTrend
+		want  string
+	}{
+		{stats.WeekTrend{ThisWeek: 5}, "↑ 新增"},
+		{stats.WeekTrend{}, "—"},
+		{stats.WeekTrend{ThisWeek: 12, LastWeek: 10, ChangePct: 20}, "↑20%"},
+		{stats.WeekTrend{ThisWeek: 5, LastWeek: 10, ChangePct: -50}, "↓50%"},
+	}
+	for _, tc := range cases {
+		if got := trendText(tc.trend); !strings.Contains(got, tc.want) {
+			t.Errorf("trendText(%+v) = %q, want %q", tc.trend, got, tc.want)
+		}
+	}
+}
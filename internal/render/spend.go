@@ -0,0 +1,49 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/nyan-statusline-cc/internal/config"
+	"github.com/nyan-statusline-cc/internal/formatter"
+	"github.com/nyan-statusline-cc/internal/ledger"
+)
+
+// spendSegment 渲染花费账本段, 如 "💸 今日$12.40/$20.00 · 本周$40.00/$100.00"
+// 今日始终显示; 本周/本月仅在设置了对应预算时显示. 颜色取各预算中最高的使用比例
+func spendSegment(t ledger.Totals, budget config.BudgetConfig) string {
+	if t.Today <= 0 && t.Month <= 0 {
+		return ""
+	}
+
+	items := []string{"今日" + spendText(t.Today, budget.Daily)}
+	if budget.Weekly > 0 {
+		items = append(items, "本周"+spendText(t.Week, budget.Weekly))
+	}
+	if budget.Monthly > 0 {
+		items = append(items, "本月"+spendText(t.Month, budget.Monthly))
+	}
+
+	ratio := max(
+		budgetRatio(t.Today, budget.Daily),
+		budgetRatio(t.Week, budget.Weekly),
+		budgetRatio(t.Month, budget.Monthly),
+	)
+	return Colorize("💸 "+strings.Join(items, " · "), BudgetColor(ratio))
+}
+
+// spendText 格式化花费, 有预算时附带预算额度
+func spendText(spent, limit float64) string {
+	text := formatter.FormatCost(spent)
+	if limit > 0 {
+		text += "/" + formatter.FormatCost(limit)
+	}
+	return text
+}
+
+// budgetRatio 花费占预算的比例, 未设预算时为 0
+func budgetRatio(spent, limit float64) float64 {
+	if limit <= 0 {
+		return 0
+	}
+	return spent / limit
+}
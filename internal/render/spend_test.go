@@ -0,0 +1,45 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nyan-statusline-cc/internal/config"
+	"github.com/nyan-statusline-cc/internal/ledger"
+)
+
+// TestSpendSegment_NoSpend 无花费时不显示
+func TestSpendSegment_NoSpend(t *testing.T) {
+	if got := spendSegment(ledger.Totals{}, config.BudgetConfig{Daily: 20}); got != "" {
+		t.Errorf("spendSegment() = %q, want empty", got)
+	}
+}
+
+// TestSpendSegment_Budgets 验证预算文本和颜色阈值
+func TestSpendSegment_Budgets(t *testing.T) {
+	tests := []struct {
+		name   string
+		totals ledger.Totals
+		budget config.BudgetConfig
+		text   string
+		color  string
+	}{
+		{"no budget", ledger.Totals{Today: 12.4, Week: 30, Month: 50}, config.BudgetConfig{}, "💸 今日$12.40", Green},
+		{"under budget", ledger.Totals{Today: 12.4}, config.BudgetConfig{Daily: 20}, "💸 今日$12.40/$20.00", Green},
+		{"near budget", ledger.Totals{Today: 17}, config.BudgetConfig{Daily: 20}, "今日$17.00/$20.00", Yellow},
+		{"over budget", ledger.Totals{Today: 25}, config.BudgetConfig{Daily: 20}, "今日$25.00/$20.00", Red},
+		{"weekly drives color", ledger.Totals{Today: 1, Week: 95}, config.BudgetConfig{Daily: 20, Weekly: 100}, "本周$95.00/$100.00", Yellow},
+		{"monthly shown", ledger.Totals{Today: 1, Month: 10}, config.BudgetConfig{Monthly: 200}, "本月$10.00/$200.00", Green},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := spendSegment(tt.totals, tt.budget)
+			if !strings.Contains(got, tt.text) {
+				t.Errorf("spendSegment() = %q, should contain %q", got, tt.text)
+			}
+			if !strings.HasPrefix(got, tt.color) {
+				t.Errorf("spendSegment() = %q, want color %q", got, tt.color)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package render
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nyan-statusline-cc/internal/animation"
+)
+
+// spritesDirName 自定义精灵目录, 位于配置文件同目录
+const spritesDirName = "sprites"
+
+// loadSprite 按名称加载精灵: 优先读取 sprites/<name>.json, 其次查找内置精灵
+// Parameters:
+//   - dir: 配置文件所在目录
+//   - name: 精灵名称, 空值表示使用默认动画
+//
+// Return:
+//   - *animation.Sprite: 帧宽已补齐的精灵, 未找到或无帧时返回 nil
+func loadSprite(dir, name string) *animation.Sprite {
+	if name == "" {
+		return nil
+	}
+
+	var sp animation.Sprite
+	raw, err := os.ReadFile(filepath.Join(dir, spritesDirName, filepath.Base(name)+".json"))
+	if err == nil {
+		if err := json.Unmarshal(raw, &sp); err != nil {
+			return nil
+		}
+	} else if builtin, ok := animation.BuiltinSprite(name); ok {
+		sp = builtin
+	}
+	if len(sp.Frames) == 0 {
+		return nil
+	}
+
+	normalizeSprite(&sp)
+	return &sp
+}
+
+// normalizeSprite 将所有帧右侧补空格到相同视觉宽度, 避免切换帧时布局抖动
+// 目标宽度取声明宽度与最宽帧中的较大值
+func normalizeSprite(sp *animation.Sprite) {
+	width := sp.Width
+	for _, f := range sp.Frames {
+		width = max(width, VisualWidth(f))
+	}
+
+	frames := make([]string, len(sp.Frames))
+	for i, f := range sp.Frames {
+		frames[i] = f + strings.Repeat(" ", width-VisualWidth(f))
+	}
+	sp.Frames = frames
+	sp.Width = width
+}
@@ -0,0 +1,61 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nyan-statusline-cc/internal/animation"
+)
+
+// TestNormalizeSprite_PadsFrames 所有帧补齐到相同视觉宽度
+func TestNormalizeSprite_PadsFrames(t *testing.T) {
+	sp := animation.Sprite{Frames: []string{"🐶", "a", "🐕🦴"}, Width: 2}
+	normalizeSprite(&sp)
+	if sp.Width != 4 {
+		t.Errorf("Width = %d, want 4 (widest frame)", sp.Width)
+	}
+	for i, f := range sp.Frames {
+		if w := VisualWidth(f); w != 4 {
+			t.Errorf("frame %d %q width = %d, want 4", i, f, w)
+		}
+	}
+}
+
+// TestBuiltinSprites_ConstantWidth 内置精灵的帧宽度与声明一致, 无需补齐
+func TestBuiltinSprites_ConstantWidth(t *testing.T) {
+	for _, name := range animation.BuiltinSpriteNames() {
+		sp, _ := animation.BuiltinSprite(name)
+		for i, f := range sp.Frames {
+			if w := VisualWidth(f); w != sp.Width {
+				t.Errorf("sprite %q frame %d %q width = %d, want %d", name, i, f, w, sp.Width)
+			}
+		}
+	}
+}
+
+// TestLoadSprite 自定义精灵文件优先于内置精灵, 未知名称返回 nil
+func TestLoadSprite(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, spritesDirName), 0755); err != nil {
+		t.Fatal(err)
+	}
+	custom := `{"name":"rocket","frames":["🛸","🛸🌟"],"frame_ms":100}`
+	if err := os.WriteFile(filepath.Join(dir, spritesDirName, "rocket.json"), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sp := loadSprite(dir, "rocket")
+	if sp == nil || sp.Frames[0] != "🛸  " || sp.FrameMs != 100 {
+		t.Errorf("loadSprite(custom) = %+v, want padded custom sprite", sp)
+	}
+	if sp := loadSprite(dir, "dog"); sp == nil || sp.Name != "dog" {
+		t.Errorf("loadSprite(dog) = %+v, want builtin", sp)
+	}
+	if sp := loadSprite(dir, "unknown"); sp != nil {
+		t.Errorf("loadSprite(unknown) = %+v, want nil", sp)
+	}
+	if sp := loadSprite(dir, ""); sp != nil {
+		t.Errorf("loadSprite(\"\") = %+v, want nil", sp)
+	}
+}
@@ -128,3 +128,25 @@ func wrapParts(parts []string, sep string, termWidth int) string {
 	}
 	return strings.Join(rendered, "\n")
 }
+
+// padRight 在右侧补空格到 width 列, 超出时截断并以 "…" 结尾 (用于表格文本列)
+func padRight(s string, width int) string {
+	if w := VisualWidth(s); w <= width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		if used+runeWidth(r) > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += runeWidth(r)
+	}
+	return b.String() + "…" + strings.Repeat(" ", width-used-1)
+}
+
+// padLeft 在左侧补空格到 width 列 (用于表格数字列), 超出时原样返回
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(0, width-VisualWidth(s))) + s
+}
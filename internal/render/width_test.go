@@ -165,3 +165,31 @@ func TestRuneWidth_CommonRanges(t *testing.T) {
 		})
 	}
 }
+
+func TestPadRight(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"abc", 5, "abc  "},
+		{"项目", 5, "项目 "},
+		{"abcdef", 5, "abcd…"},
+		{"项目名称", 5, "项目…"},
+	}
+	for _, tt := range tests {
+		got := padRight(tt.in, tt.width)
+		if got != tt.want || VisualWidth(got) != tt.width {
+			t.Errorf("padRight(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestPadLeft(t *testing.T) {
+	if got := padLeft("会话", 6); got != "  会话" {
+		t.Errorf("padLeft(会话, 6) = %q", got)
+	}
+	if got := padLeft("1234567", 3); got != "1234567" {
+		t.Errorf("padLeft(overflow) = %q", got)
+	}
+}
@@ -0,0 +1,83 @@
+// Package safefile 为多个状态栏进程共享的状态文件提供原子写入与文件锁
+//
+// 多个会话同时渲染时, 原地覆盖写入会让其他进程读到半个文件;
+// 先写同目录的临时文件再 rename, 读取方只会看到完整的旧文件或新文件.
+package safefile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// Write 原子写入文件: 写入同目录临时文件后 rename 覆盖目标
+// Parameters:
+//   - path: 目标文件路径
+//   - data: 文件内容
+//
+// Return:
+//   - error: 写入或重命名错误, 出错时目标文件保持不变
+func Write(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	name := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(name)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(name)
+		return err
+	}
+	if err := os.Chmod(name, 0644); err != nil {
+		os.Remove(name)
+		return err
+	}
+	if err := os.Rename(name, path); err != nil {
+		os.Remove(name)
+		return err
+	}
+	return nil
+}
+
+// Quarantine 将无法解析的文件改名为 path + ".corrupt" 保留, 之后可安全写入新文件
+// 已有 .corrupt 备份时改用带时间戳的文件名, 不覆盖旧备份
+// Parameters:
+//   - path: 损坏的文件路径
+//
+// Return:
+//   - error: 重命名错误 (此时不应覆盖原文件)
+func Quarantine(path string) error {
+	backup := path + ".corrupt"
+	if _, err := os.Stat(backup); err == nil {
+		backup = fmt.Sprintf("%s.corrupt-%d", path, time.Now().UnixMilli())
+	}
+	return os.Rename(path, backup)
+}
+
+// Lock 获取 path 对应的独占文件锁 (path + ".lock"), 阻塞直到获得
+// Parameters:
+//   - path: 受保护的文件路径
+//
+// Return:
+//   - func(): 释放锁
+//   - error: 创建锁文件或加锁错误
+func Lock(path string) (func(), error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
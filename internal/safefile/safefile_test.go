@@ -0,0 +1,77 @@
+package safefile
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestWrite 覆盖已有文件且不留下临时文件
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(path, []byte("new")); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "new" {
+		t.Errorf("content = %q, want new", got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("dir has %d entries, want only the target file", len(entries))
+	}
+}
+
+// TestWrite_MissingDir 目录不存在时返回错误
+func TestWrite_MissingDir(t *testing.T) {
+	if err := Write(filepath.Join(t.TempDir(), "none", "state.json"), []byte("x")); err == nil {
+		t.Error("Write() into missing dir should fail")
+	}
+}
+
+// TestQuarantine 损坏文件改名保留, 已有备份时不覆盖
+func TestQuarantine(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	for _, content := range []string{"first", "second"} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Quarantine(path); err != nil {
+			t.Fatalf("Quarantine() error: %v", err)
+		}
+	}
+	if raw, _ := os.ReadFile(path + ".corrupt"); string(raw) != "first" {
+		t.Errorf("first backup = %q, want first", raw)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("dir has %d entries, want two backups", len(entries))
+	}
+}
+
+// TestLock 加锁后的读-改-写不会丢失更新
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := Lock(path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer unlock()
+			data, _ := os.ReadFile(path)
+			_ = Write(path, append(data, 'x'))
+		}()
+	}
+	wg.Wait()
+	if data, _ := os.ReadFile(path); len(data) != 20 {
+		t.Errorf("counter = %d, want 20", len(data))
+	}
+}
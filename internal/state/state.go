@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 const stateFileName = "nyan-state.json"
@@ -21,44 +22,103 @@ const (
 
 // stateData 状态文件结构
 type stateData struct {
-	Status string `json:"status"`
+	Status     string `json:"status"`
+	ChangedAt  int64  `json:"changed_at,omitempty"`   // 状态切换时间 (Unix 毫秒)
+	TurnMs     int64  `json:"turn_ms,omitempty"`      // 最近一轮处理时长 (毫秒)
+	BestTurnMs int64  `json:"best_turn_ms,omitempty"` // 历史最长一轮处理时长 (毫秒)
+	Record     bool   `json:"record,omitempty"`       // 最近一轮是否刷新历史最长
+}
+
+// Info 状态文件内容
+type Info struct {
+	Status       string
+	ChangedAt    time.Time     // 旧格式状态文件无此字段时为零值
+	TurnDuration time.Duration // 最近一轮处理时长, 仅 processing → completed 时记录
+	BestTurn     time.Duration // 历史最长一轮处理时长
+	Record       bool          // 最近一轮是否刷新历史最长 (首轮不算)
 }
 
 // SetStatus 将指定状态写入状态文件
 // Parameters:
-//   - binaryDir: 二进制文件所在目录 (状态文件同目录)
+//   - dataDir: nyan-statusline 数据目录 (状态文件所在目录)
 //   - status: 状态值 (processing/completed)
 //
 // Return:
 //   - error: 错误信息
-func SetStatus(binaryDir, status string) error {
-	statePath := filepath.Join(binaryDir, stateFileName)
-	data, err := json.Marshal(stateData{Status: status})
+func SetStatus(dataDir, status string) error {
+	return setStatusAt(dataDir, status, time.Now())
+}
+
+// setStatusAt 以指定时间写入状态, 便于测试
+// processing → completed 时记录本轮处理时长并更新历史最长纪录
+func setStatusAt(dataDir, status string, now time.Time) error {
+	prev := readStateData(dataDir)
+	next := stateData{
+		Status:     status,
+		ChangedAt:  now.UnixMilli(),
+		BestTurnMs: prev.BestTurnMs,
+	}
+	if status == StatusCompleted && prev.Status == StatusProcessing && prev.ChangedAt > 0 {
+		next.TurnMs = max(now.UnixMilli()-prev.ChangedAt, 0)
+		if next.TurnMs > prev.BestTurnMs {
+			next.Record = prev.BestTurnMs > 0
+			next.BestTurnMs = next.TurnMs
+		}
+	}
+
+	statePath := filepath.Join(dataDir, stateFileName)
+	data, err := json.Marshal(next)
 	if err != nil {
 		return err
 	}
 	return os.WriteFile(statePath, data, 0644)
 }
 
-// IsProcessing 读取状态文件, 判断 Claude Code 是否正在处理中
+// Load 读取状态文件
 // Parameters:
-//   - binaryDir: 二进制文件所在目录 (状态文件同目录)
+//   - dataDir: nyan-statusline 数据目录 (状态文件所在目录)
 //
 // Return:
-//   - bool: true 表示正在处理, false 表示已完成
-func IsProcessing(binaryDir string) bool {
-	statePath := filepath.Join(binaryDir, stateFileName)
-
+//   - *Info: 状态信息, 文件不存在或损坏时返回 nil
+func Load(dataDir string) *Info {
+	statePath := filepath.Join(dataDir, stateFileName)
 	raw, err := os.ReadFile(statePath)
 	if err != nil {
-		// 无状态文件, 默认处理中
-		return true
+		return nil
 	}
-
 	var s stateData
 	if err := json.Unmarshal(raw, &s); err != nil {
-		return true
+		return nil
+	}
+	info := &Info{
+		Status:       s.Status,
+		TurnDuration: time.Duration(s.TurnMs) * time.Millisecond,
+		BestTurn:     time.Duration(s.BestTurnMs) * time.Millisecond,
+		Record:       s.Record,
 	}
+	if s.ChangedAt > 0 {
+		info.ChangedAt = time.UnixMilli(s.ChangedAt)
+	}
+	return info
+}
+
+// readStateData 读取原始状态数据, 文件不存在或损坏时返回零值
+func readStateData(dataDir string) stateData {
+	var s stateData
+	if raw, err := os.ReadFile(filepath.Join(dataDir, stateFileName)); err == nil {
+		_ = json.Unmarshal(raw, &s)
+	}
+	return s
+}
 
-	return s.Status != StatusCompleted
+// IsProcessing 读取状态文件, 判断 Claude Code 是否正在处理中
+// Parameters:
+//   - dataDir: nyan-statusline 数据目录 (状态文件所在目录)
+//
+// Return:
+//   - bool: true 表示正在处理, false 表示已完成
+func IsProcessing(dataDir string) bool {
+	// 无状态文件或文件损坏, 默认处理中
+	s := Load(dataDir)
+	return s == nil || s.Status != StatusCompleted
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // TestIsProcessing_NoStateFile 无状态文件时应返回 true (默认处理中)
@@ -126,3 +127,92 @@ func writeState(t *testing.T, dir, content string) {
 		t.Fatalf("failed to write state file: %v", err)
 	}
 }
+
+// TestLoad_NoStateFile 无状态文件时应返回 nil
+func TestLoad_NoStateFile(t *testing.T) {
+	if s := Load(t.TempDir()); s != nil {
+		t.Errorf("Load() without state file = %+v, want nil", s)
+	}
+}
+
+// TestLoad_LegacyFormat 旧格式无 changed_at 时 ChangedAt 应为零值
+func TestLoad_LegacyFormat(t *testing.T) {
+	dir := t.TempDir()
+	writeState(t, dir, `{"status":"completed"}`)
+
+	s := Load(dir)
+	if s == nil || s.Status != StatusCompleted {
+		t.Fatalf("Load() = %+v, want completed", s)
+	}
+	if !s.ChangedAt.IsZero() {
+		t.Errorf("ChangedAt = %v, want zero", s.ChangedAt)
+	}
+}
+
+// TestSetStatus_RecordsChangedAt 写入状态时应记录切换时间
+func TestSetStatus_RecordsChangedAt(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 2, 26, 10, 0, 0, 0, time.UTC)
+	if err := setStatusAt(dir, StatusProcessing, now); err != nil {
+		t.Fatalf("setStatusAt() error: %v", err)
+	}
+
+	s := Load(dir)
+	if s == nil {
+		t.Fatal("Load() should return state after setStatusAt")
+	}
+	if !s.ChangedAt.Equal(now) {
+		t.Errorf("ChangedAt = %v, want %v", s.ChangedAt, now)
+	}
+}
+
+// TestSetStatus_TurnDuration processing → completed 应记录本轮处理时长
+func TestSetStatus_TurnDuration(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 2, 26, 10, 0, 0, 0, time.UTC)
+	_ = setStatusAt(dir, StatusProcessing, start)
+	_ = setStatusAt(dir, StatusCompleted, start.Add(90*time.Second))
+
+	s := Load(dir)
+	if s.TurnDuration != 90*time.Second {
+		t.Errorf("TurnDuration = %v, want 90s", s.TurnDuration)
+	}
+	if s.BestTurn != 90*time.Second {
+		t.Errorf("BestTurn = %v, want 90s", s.BestTurn)
+	}
+	if s.Record {
+		t.Error("first turn should not count as a record")
+	}
+}
+
+// TestSetStatus_Record 超过历史最长时应标记纪录, 未超过时保留历史最长
+func TestSetStatus_Record(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 2, 26, 10, 0, 0, 0, time.UTC)
+	turn := func(d time.Duration) *Info {
+		_ = setStatusAt(dir, StatusProcessing, start)
+		_ = setStatusAt(dir, StatusCompleted, start.Add(d))
+		return Load(dir)
+	}
+
+	turn(time.Minute)
+	if s := turn(2 * time.Minute); !s.Record || s.BestTurn != 2*time.Minute {
+		t.Errorf("longer turn: Record=%v BestTurn=%v, want true 2m", s.Record, s.BestTurn)
+	}
+	if s := turn(30 * time.Second); s.Record || s.BestTurn != 2*time.Minute {
+		t.Errorf("shorter turn: Record=%v BestTurn=%v, want false 2m", s.Record, s.BestTurn)
+	}
+}
+
+// TestSetStatus_CompletedTwice 重复 completed 不应产生新的处理时长
+func TestSetStatus_CompletedTwice(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 2, 26, 10, 0, 0, 0, time.UTC)
+	_ = setStatusAt(dir, StatusProcessing, start)
+	_ = setStatusAt(dir, StatusCompleted, start.Add(time.Minute))
+	_ = setStatusAt(dir, StatusCompleted, start.Add(2*time.Minute))
+
+	if s := Load(dir); s.TurnDuration != 0 {
+		t.Errorf("TurnDuration = %v, want 0 after repeated completed", s.TurnDuration)
+	}
+}
@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/model"
+)
+
+// dayStartHour 统计日的起始小时 (0-23), 由 SetDayStart 在启动时设置
+var dayStartHour = 0
+
+// SetDayStart 设置统计日的起始小时, 该小时之前的活动计入前一天
+// 例如设为 4 时, 凌晨 3 点仍算作前一天; 超出 0-23 的值按 0 处理
+// Parameters:
+//   - hour: 起始小时
+func SetDayStart(hour int) {
+	if hour < 0 || hour > 23 {
+		hour = 0
+	}
+	dayStartHour = hour
+}
+
+// civilDate 返回 t 所在的统计日 (按 t 自身的时区和起始小时划分)
+// 结果以 UTC 零点表示, 按天加减和相减不受夏令时影响
+func civilDate(t time.Time) time.Time {
+	return dateAt(t, dayStartHour)
+}
+
+// dateAt 返回 t 按给定起始小时划分的日期, 以 UTC 零点表示
+func dateAt(t time.Time, startHour int) time.Time {
+	y, m, d := t.Date()
+	if t.Hour() < startHour {
+		d--
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// cacheDate 返回 t 按缓存日期的划分方式所在的日期
+// stats-cache.json 的日期由 Claude Code 按日历日期生成, 不应用起始小时, 两边按同一规则比较
+func cacheDate(cache *model.StatsCache, t time.Time) time.Time {
+	return dateAt(t, cache.DayStartHour)
+}
+
+// Today 返回 now 所在的统计日 (按时区与 SetDayStart 设置的起始小时), 以 UTC 零点表示
+// Parameters:
+//   - now: 当前时间
+//
+// Return:
+//   - time.Time: 统计日
+func Today(now time.Time) time.Time {
+	return civilDate(now)
+}
+
+// truncateToDate 取 t 的日历日期 (忽略起始小时), 以 UTC 零点表示
+func truncateToDate(t time.Time) time.Time {
+	return dateAt(t, 0)
+}
+
+// daysBetween 两个日期 (UTC 零点) 相差的天数
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from) / (24 * time.Hour))
+}
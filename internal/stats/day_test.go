@@ -0,0 +1,154 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/model"
+)
+
+// withDayStart 在测试期间设置统计日起始小时, 结束后恢复
+func withDayStart(t *testing.T, hour int) {
+	t.Helper()
+	SetDayStart(hour)
+	t.Cleanup(func() { SetDayStart(0) })
+}
+
+// mustLoad 加载时区, 系统缺少时区数据时跳过测试
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s unavailable: %v", name, err)
+	}
+	return loc
+}
+
+func TestSetDayStart_OutOfRange(t *testing.T) {
+	withDayStart(t, 24)
+	if dayStartHour != 0 {
+		t.Errorf("dayStartHour = %d, want 0 for out-of-range hour", dayStartHour)
+	}
+}
+
+func TestCivilDate(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	tests := []struct {
+		name     string
+		dayStart int
+		at       time.Time
+		want     string
+	}{
+		{"midnight", 0, time.Date(2026, 3, 1, 0, 0, 0, 0, loc), "2026-03-01"},
+		{"before day start", 4, time.Date(2026, 3, 1, 3, 59, 0, 0, loc), "2026-02-28"},
+		{"at day start", 4, time.Date(2026, 3, 1, 4, 0, 0, 0, loc), "2026-03-01"},
+		{"new year", 4, time.Date(2026, 1, 1, 1, 0, 0, 0, loc), "2025-12-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withDayStart(t, tt.dayStart)
+			got := civilDate(tt.at)
+			if got.Format(dateLayout) != tt.want || got.Location() != time.UTC || got.Hour() != 0 {
+				t.Errorf("civilDate() = %v, want %s 00:00 UTC", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestComputeStatsInfo_FirstSessionUTC UTC 的首次会话时间按 now 的时区取日期
+func TestComputeStatsInfo_FirstSessionUTC(t *testing.T) {
+	la := time.FixedZone("PST", -8*3600)
+	// 2026-02-20T02:00Z 在 UTC-8 仍是 2-19
+	cache := &model.StatsCache{FirstSessionDate: "2026-02-20T02:00:00Z"}
+	now := time.Date(2026, 2, 26, 20, 0, 0, 0, la)
+	if got := ComputeStatsInfo(cache, now).CodingDays; got != 8 {
+		t.Errorf("CodingDays = %d, want 8 (2-19 ~ 2-26)", got)
+	}
+}
+
+// TestComputeStatsInfo_DST 夏令时切换日 (23/25 小时) 不影响天数与连续活跃
+func TestComputeStatsInfo_DST(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	tests := []struct {
+		name  string
+		first string
+		now   time.Time
+		days  int
+	}{
+		// 2026-03-08 开始夏令时
+		{"spring forward", "2026-03-01T00:00:00-05:00", time.Date(2026, 3, 10, 0, 30, 0, 0, ny), 10},
+		// 2026-11-01 结束夏令时
+		{"fall back", "2026-10-25T00:00:00-04:00", time.Date(2026, 11, 2, 23, 30, 0, 0, ny), 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			today := tt.now.Format(dateLayout)
+			var activity []model.DailyActivity
+			for i := tt.days - 1; i >= 0; i-- {
+				date := tt.now.AddDate(0, 0, -i).Format(dateLayout)
+				activity = append(activity, model.DailyActivity{Date: date, MessageCount: 1, SessionCount: 1})
+			}
+			cache := &model.StatsCache{FirstSessionDate: tt.first, DailyActivity: activity}
+			info := ComputeStatsInfo(cache, tt.now)
+			if info.CodingDays != tt.days {
+				t.Errorf("CodingDays = %d, want %d", info.CodingDays, tt.days)
+			}
+			if info.Streak != tt.days {
+				t.Errorf("Streak = %d, want %d", info.Streak, tt.days)
+			}
+			if info.TodayMessages != 1 || activity[len(activity)-1].Date != today {
+				t.Errorf("TodayMessages = %d, want today's activity", info.TodayMessages)
+			}
+			if got := info.DailyMessages[dailyWindow-tt.days:]; len(got) != tt.days || got[0] != 1 {
+				t.Errorf("DailyMessages tail = %v, want %d ones", got, tt.days)
+			}
+		})
+	}
+}
+
+// TestComputeStatsInfo_DayStart 按起始小时划分的缓存: 起始小时之前, "今天" 仍是前一天
+func TestComputeStatsInfo_DayStart(t *testing.T) {
+	withDayStart(t, 4)
+	cache := &model.StatsCache{
+		DayStartHour:     4,
+		FirstSessionDate: "2026-02-24T12:00:00Z",
+		DailyActivity: []model.DailyActivity{
+			{Date: "2026-02-24", MessageCount: 10, SessionCount: 1},
+			{Date: "2026-02-25", MessageCount: 20, SessionCount: 1},
+		},
+	}
+	// 2-26 凌晨 2 点: 统计日仍是 2-25
+	now := time.Date(2026, 2, 26, 2, 0, 0, 0, time.UTC)
+	info := ComputeStatsInfo(cache, now)
+	if info.TodayMessages != 20 {
+		t.Errorf("TodayMessages = %d, want 20 (2-25)", info.TodayMessages)
+	}
+	if info.CodingDays != 2 {
+		t.Errorf("CodingDays = %d, want 2", info.CodingDays)
+	}
+	if info.Streak != 2 {
+		t.Errorf("Streak = %d, want 2", info.Streak)
+	}
+
+	// stats-cache.json 按日历日期: 起始小时不改变 "今天", 与文件中的日期一致
+	cache.DayStartHour = 0
+	cache.DailyActivity = append(cache.DailyActivity, model.DailyActivity{Date: "2026-02-26", MessageCount: 5, SessionCount: 1})
+	info = ComputeStatsInfo(cache, now)
+	if info.TodayMessages != 5 || info.CodingDays != 3 || info.Streak != 3 {
+		t.Errorf("calendar cache: today = %d, days = %d, streak = %d; want 5/3/3", info.TodayMessages, info.CodingDays, info.Streak)
+	}
+}
+
+// TestBuildReport_DayStart 显式指定的日期不受起始小时影响
+func TestBuildReport_DayStart(t *testing.T) {
+	withDayStart(t, 4)
+	now := time.Date(2026, 2, 26, 2, 0, 0, 0, time.UTC)
+	r := BuildReport(&model.StatsCache{DayStartHour: 4}, time.Time{}, time.Time{}, now)
+	if r.Until != "2026-02-25" {
+		t.Errorf("default Until = %s, want 2026-02-25", r.Until)
+	}
+	until := time.Date(2026, 2, 26, 0, 0, 0, 0, time.Local)
+	if r := BuildReport(&model.StatsCache{}, until, until, now); r.Since != "2026-02-26" || r.Until != "2026-02-26" {
+		t.Errorf("explicit range = %s ~ %s, want 2026-02-26", r.Since, r.Until)
+	}
+}
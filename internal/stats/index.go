@@ -0,0 +1,528 @@
+package stats
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/model"
+	"github.com/nyan-statusline-cc/internal/safefile"
+	"github.com/nyan-statusline-cc/internal/transcript"
+)
+
+const indexFileName = "nyan-stats-index.json"
+
+// indexVersion 索引格式版本
+//   - 1: 读取进度、整小时与会话汇总
+//   - 2: 按 15 分钟时段分桶, 增加项目活动 (含按模型的请求数与 token 用量) 和每个文件计入的活动
+//
+// 旧版本的索引保留已计入的时段与会话, 只从仍存在的会话记录补建项目与文件活动
+const indexVersion = 2
+
+// syntheticModel Claude Code 本地生成消息使用的模型名
+const syntheticModel = "<synthetic>"
+
+// 索引按 UTC 时段分桶, 汇总时再换算到本地日期
+// 时段为 15 分钟: 所有时区偏移都是 15 分钟的整数倍, 每个时段只落在一个本地日期内
+// (整小时分桶在 +05:30/+09:30/+05:45 等时区会跨越午夜或起始小时)
+const (
+	slotLength = 15 * time.Minute
+	slotLayout = "2006-01-02T15:04"
+	hourLayout = "2006-01-02T15" // 旧版本索引的整小时分桶, 仅用于读取
+)
+
+// slotKey 返回 t 所在 UTC 时段的 key
+func slotKey(t time.Time) string {
+	return t.UTC().Truncate(slotLength).Format(slotLayout)
+}
+
+// parseSlot 解析时段 key, 兼容旧版本的整小时 key
+func parseSlot(key string) (time.Time, error) {
+	if len(key) == len(hourLayout) {
+		return time.Parse(hourLayout, key)
+	}
+	return time.Parse(slotLayout, key)
+}
+
+// fileOffset 单个会话记录文件的读取进度及其计入的活动
+type fileOffset struct {
+	Offset  int64                `json:"offset"`          // 已读取的字节数
+	LastKey string               `json:"last_key"`        // 最后一条 assistant 消息的去重键, 跨增量读取去重
+	Hours   map[string]*fileHour `json:"hours,omitempty"` // UTC 时段 → 本文件计入的活动, 文件被重写时据此扣除
+}
+
+// fileHour 单个文件在一个 UTC 时段内计入的活动
+type fileHour struct {
+	projectHour
+	Sessions []string `json:"sessions,omitempty"`
+}
+
+// hourBucket 一个 UTC 时段内的活动
+type hourBucket struct {
+	Messages int      `json:"messages"`
+	Sessions []string `json:"sessions"` // 该时段内有消息的会话
+}
+
+// Tokens 按类别累计的 token 用量
+type Tokens struct {
+	Input      int64 `json:"input"`
+	Output     int64 `json:"output"`
+	CacheWrite int64 `json:"cache_write"`
+	CacheRead  int64 `json:"cache_read"`
+}
+
+// Total 四类 token 之和
+func (t Tokens) Total() int64 {
+	return t.Input + t.Output + t.CacheWrite + t.CacheRead
+}
+
+// add 累加另一份用量
+func (t *Tokens) add(o Tokens) {
+	t.Input += o.Input
+	t.Output += o.Output
+	t.CacheWrite += o.CacheWrite
+	t.CacheRead += o.CacheRead
+}
+
+// modelUsage 单个模型的请求数与 token 用量
+type modelUsage struct {
+	Requests int `json:"requests"` // 去重后的 assistant 消息数
+	Tokens
+}
+
+// projectHour 项目在一个 UTC 时段内的活动
+type projectHour struct {
+	Messages int                    `json:"messages"`
+	Models   map[string]*modelUsage `json:"models,omitempty"` // model.id → 用量
+}
+
+// add 计入一条消息及其 token 用量
+func (h *projectHour) add(e transcript.Entry) {
+	h.Messages++
+
+	// <synthetic> 是 Claude Code 本地生成的消息 (如错误提示), 不是真实模型
+	if e.Message == nil || e.Message.Usage == nil || e.Message.Model == "" || e.Message.Model == syntheticModel {
+		return
+	}
+	if h.Models == nil {
+		h.Models = make(map[string]*modelUsage)
+	}
+	m := h.Models[e.Message.Model]
+	if m == nil {
+		m = &modelUsage{}
+		h.Models[e.Message.Model] = m
+	}
+	m.Requests++
+	u := e.Message.Usage
+	m.add(Tokens{Input: u.InputTokens, Output: u.OutputTokens, CacheWrite: u.CacheCreationInputTokens, CacheRead: u.CacheReadInputTokens})
+}
+
+// sub 扣除另一份活动
+func (h *projectHour) sub(o projectHour) {
+	h.Messages -= o.Messages
+	for id, u := range o.Models {
+		m := h.Models[id]
+		if m == nil {
+			continue
+		}
+		m.Requests -= u.Requests
+		m.add(Tokens{Input: -u.Input, Output: -u.Output, CacheWrite: -u.CacheWrite, CacheRead: -u.CacheRead})
+		if m.Requests <= 0 {
+			delete(h.Models, id)
+		}
+	}
+}
+
+// projectBucket 单个项目 (projects/ 下的一个目录) 的活动
+type projectBucket struct {
+	Cwd      string                  `json:"cwd"`      // 项目目录 (首条带工作目录的记录)
+	Sessions []string                `json:"sessions"` // 会话 ID
+	Hours    map[string]*projectHour `json:"hours"`    // UTC 时段 → 活动
+}
+
+// Index 会话记录增量索引
+// 会话记录可能被 Claude Code 定期清理, 已计入的活动在文件删除后仍保留
+type Index struct {
+	Version  int                       `json:"version"`
+	Files    map[string]fileOffset     `json:"files"`
+	Hours    map[string]*hourBucket    `json:"hours"`    // UTC 时段 → 活动
+	Sessions map[string]int64          `json:"sessions"` // 会话 ID → 首条消息时间 (Unix 毫秒)
+	Projects map[string]*projectBucket `json:"projects"` // projects/ 下的目录名 → 活动
+
+	migrated bool // 从旧版本读取, 需要补建项目与文件活动
+	corrupt  bool // 索引文件无法解析, 保存前先改名保留
+}
+
+// newIndex 创建空索引
+func newIndex() *Index {
+	return &Index{
+		Version:  indexVersion,
+		Files:    make(map[string]fileOffset),
+		Hours:    make(map[string]*hourBucket),
+		Sessions: make(map[string]int64),
+		Projects: make(map[string]*projectBucket),
+	}
+}
+
+// loadIndex 读取索引, 文件不存在或损坏时返回空索引 (损坏时标记 corrupt, 从会话记录重建)
+// 版本不一致时保留读取进度与已计入的时段、会话, 项目与文件活动由 UpdateIndex 补建
+func loadIndex(dataDir string) *Index {
+	idx := newIndex()
+	raw, err := os.ReadFile(filepath.Join(dataDir, indexFileName))
+	if err != nil {
+		return idx
+	}
+	var stored Index
+	if json.Unmarshal(raw, &stored) != nil {
+		idx.corrupt = true
+		return idx
+	}
+	if stored.Files != nil {
+		idx.Files = stored.Files
+	}
+	if stored.Hours != nil {
+		idx.Hours = stored.Hours
+	}
+	if stored.Sessions != nil {
+		idx.Sessions = stored.Sessions
+	}
+	if stored.Version != indexVersion {
+		idx.migrated = true
+		return idx
+	}
+	if stored.Projects != nil {
+		idx.Projects = stored.Projects
+	}
+	return idx
+}
+
+// UpdateIndex 增量扫描会话记录并原子保存索引
+// 文件变短 (被重写) 时扣除该文件计入的活动后从头读取; 已删除文件不再跟踪读取进度, 其活动保留
+// Parameters:
+//   - claudeDir: Claude Code 数据目录 (包含 projects/)
+//   - dataDir: 索引文件所在目录
+//
+// Return:
+//   - *Index: 更新后的索引
+//   - error: 写入错误
+func UpdateIndex(claudeDir, dataDir string) (*Index, error) {
+	idx := loadIndex(dataDir)
+	files := transcript.Files(claudeDir, time.Time{})
+
+	sizes := make(map[string]int64, len(files))
+	for _, path := range files {
+		if fi, err := os.Stat(path); err == nil {
+			sizes[path] = fi.Size()
+		}
+	}
+
+	changed := false
+	// 一个会话记录都没有时可能是数据目录暂不可用, 保留读取进度以免之后重复计入
+	if len(sizes) > 0 {
+		for path := range idx.Files {
+			if _, ok := sizes[path]; !ok {
+				delete(idx.Files, path)
+				changed = true
+			}
+		}
+	}
+	if idx.migrated {
+		for path := range idx.Files {
+			idx.backfill(path)
+		}
+		changed = true
+	}
+	for _, path := range files {
+		size, ok := sizes[path]
+		if !ok || size == idx.Files[path].Offset {
+			continue
+		}
+		if size < idx.Files[path].Offset {
+			idx.drop(path)
+			changed = true
+		}
+		if idx.scan(path) {
+			changed = true
+		}
+	}
+	if !changed {
+		return idx, nil
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return idx, err
+	}
+	path := filepath.Join(dataDir, indexFileName)
+	// 损坏的索引可能含有已删除会话记录的活动, 改名保留而不是直接覆盖
+	if idx.corrupt {
+		if err := safefile.Quarantine(path); err != nil {
+			return idx, err
+		}
+		idx.corrupt = false
+	}
+	return idx, safefile.Write(path, data)
+}
+
+// scan 从上次偏移处继续读取文件, 返回是否有进度
+func (idx *Index) scan(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	project := filepath.Base(filepath.Dir(path))
+	pos := idx.Files[path]
+	if _, err := f.Seek(pos.Offset, 0); err != nil {
+		return false
+	}
+	consumed, _ := transcript.Read(f, func(e transcript.Entry) {
+		if !e.IsMessage() {
+			return
+		}
+		// 流式响应的同一条 assistant 消息会连续写入多行
+		if key := e.DedupKey(); key != "" {
+			if key == pos.LastKey {
+				return
+			}
+			pos.LastKey = key
+		}
+		idx.add(e)
+		idx.addProject(project, e)
+		pos.add(e)
+	})
+	if consumed == 0 {
+		return false
+	}
+	pos.Offset += consumed
+	idx.Files[path] = pos
+	return true
+}
+
+// backfill 重新读取旧版本索引已计入的部分 (读取进度之前), 只补建项目与文件活动
+// 时段与会话汇总已包含这部分, 不重复计入; 文件已删除时其项目活动无法补建
+func (idx *Index) backfill(path string) {
+	pos := idx.Files[path]
+	pos.Hours = nil
+	defer func() { idx.Files[path] = pos }()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	project := filepath.Base(filepath.Dir(path))
+	lastKey := ""
+	_, _ = transcript.Read(io.LimitReader(f, pos.Offset), func(e transcript.Entry) {
+		if !e.IsMessage() {
+			return
+		}
+		if key := e.DedupKey(); key != "" {
+			if key == lastKey {
+				return
+			}
+			lastKey = key
+		}
+		idx.addProject(project, e)
+		pos.add(e)
+	})
+}
+
+// add 计入一条消息
+func (idx *Index) add(e transcript.Entry) {
+	at := e.Timestamp.UTC()
+	key := slotKey(at)
+	b := idx.Hours[key]
+	if b == nil {
+		b = &hourBucket{}
+		idx.Hours[key] = b
+	}
+	b.Messages++
+
+	if e.SessionID == "" {
+		return
+	}
+	if !slices.Contains(b.Sessions, e.SessionID) {
+		b.Sessions = append(b.Sessions, e.SessionID)
+	}
+	if first, ok := idx.Sessions[e.SessionID]; !ok || at.UnixMilli() < first {
+		idx.Sessions[e.SessionID] = at.UnixMilli()
+	}
+}
+
+// addProject 计入项目的一条消息及其 token 用量
+func (idx *Index) addProject(project string, e transcript.Entry) {
+	p := idx.Projects[project]
+	if p == nil {
+		p = &projectBucket{Hours: make(map[string]*projectHour)}
+		idx.Projects[project] = p
+	}
+	if p.Cwd == "" {
+		p.Cwd = e.Cwd
+	}
+	if e.SessionID != "" && !slices.Contains(p.Sessions, e.SessionID) {
+		p.Sessions = append(p.Sessions, e.SessionID)
+	}
+
+	key := slotKey(e.Timestamp)
+	h := p.Hours[key]
+	if h == nil {
+		h = &projectHour{}
+		p.Hours[key] = h
+	}
+	h.add(e)
+}
+
+// add 记录文件计入的一条消息, 用于文件被重写时扣除
+func (f *fileOffset) add(e transcript.Entry) {
+	key := slotKey(e.Timestamp)
+	if f.Hours == nil {
+		f.Hours = make(map[string]*fileHour)
+	}
+	h := f.Hours[key]
+	if h == nil {
+		h = &fileHour{}
+		f.Hours[key] = h
+	}
+	h.add(e)
+	if e.SessionID != "" && !slices.Contains(h.Sessions, e.SessionID) {
+		h.Sessions = append(h.Sessions, e.SessionID)
+	}
+}
+
+// drop 从汇总中扣除文件计入的活动并清除其读取进度, 之后从头读取该文件
+// 其他仍在跟踪的文件也计入了同一会话时保留该会话
+func (idx *Index) drop(path string) {
+	pos := idx.Files[path]
+	delete(idx.Files, path)
+
+	project := idx.Projects[filepath.Base(filepath.Dir(path))]
+	sessions := make(map[string]struct{})
+	for key, fh := range pos.Hours {
+		hk := idx.bucketKey(key)
+		if b := idx.Hours[hk]; b != nil {
+			b.Messages -= fh.Messages
+			b.Sessions = slices.DeleteFunc(b.Sessions, func(s string) bool {
+				return slices.Contains(fh.Sessions, s) && !idx.tracked(key, s)
+			})
+			if b.Messages <= 0 {
+				delete(idx.Hours, hk)
+			}
+		}
+		if project != nil {
+			if h := project.Hours[key]; h != nil {
+				h.sub(fh.projectHour)
+				if h.Messages <= 0 {
+					delete(project.Hours, key)
+				}
+			}
+		}
+		for _, s := range fh.Sessions {
+			sessions[s] = struct{}{}
+		}
+	}
+
+	for s := range sessions {
+		if idx.tracked("", s) {
+			continue
+		}
+		delete(idx.Sessions, s)
+		if project != nil {
+			project.Sessions = slices.DeleteFunc(project.Sessions, func(id string) bool { return id == s })
+		}
+	}
+}
+
+// bucketKey 返回时段 key 对应的汇总 key; 从旧版本迁移的活动按整小时分桶, 找不到时段时退回所在小时
+func (idx *Index) bucketKey(key string) string {
+	if _, ok := idx.Hours[key]; !ok {
+		if hour := key[:len(hourLayout)]; idx.Hours[hour] != nil {
+			return hour
+		}
+	}
+	return key
+}
+
+// tracked 仍在跟踪的文件是否在该时段 (为空时不限时段) 计入过会话
+func (idx *Index) tracked(slot, session string) bool {
+	for _, f := range idx.Files {
+		for key, h := range f.Hours {
+			if (slot == "" || key == slot) && slices.Contains(h.Sessions, session) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Cache 将索引汇总为与 stats-cache.json 等价的统计缓存
+// Parameters:
+//   - loc: 日期与小时使用的时区, 日期按 SetDayStart 设置的起始小时划分
+//
+// Return:
+//   - *model.StatsCache: 统计缓存 (HourCounts 按会话开始时间统计)
+func (idx *Index) Cache(loc *time.Location) *model.StatsCache {
+	cache := &model.StatsCache{
+		TotalSessions: len(idx.Sessions),
+		HourCounts:    make(map[string]int),
+		DayStartHour:  dayStartHour,
+	}
+
+	type day struct {
+		messages int
+		sessions map[string]struct{}
+	}
+	days := make(map[string]*day)
+	for key, b := range idx.Hours {
+		at, err := parseSlot(key)
+		if err != nil {
+			continue
+		}
+		date := civilDate(at.In(loc)).Format(dateLayout)
+		d := days[date]
+		if d == nil {
+			d = &day{sessions: make(map[string]struct{})}
+			days[date] = d
+		}
+		d.messages += b.Messages
+		for _, s := range b.Sessions {
+			d.sessions[s] = struct{}{}
+		}
+		cache.TotalMessages += b.Messages
+	}
+	for date, d := range days {
+		ids := make([]string, 0, len(d.sessions))
+		for s := range d.sessions {
+			ids = append(ids, s)
+		}
+		sort.Strings(ids)
+		cache.DailyActivity = append(cache.DailyActivity, model.DailyActivity{
+			Date:         date,
+			MessageCount: d.messages,
+			SessionCount: len(d.sessions),
+			SessionIDs:   ids,
+		})
+	}
+	sort.Slice(cache.DailyActivity, func(i, j int) bool {
+		return cache.DailyActivity[i].Date < cache.DailyActivity[j].Date
+	})
+
+	var first int64
+	for _, ms := range idx.Sessions {
+		cache.HourCounts[strconv.Itoa(time.UnixMilli(ms).In(loc).Hour())]++
+		if first == 0 || ms < first {
+			first = ms
+		}
+	}
+	if first > 0 {
+		cache.FirstSessionDate = time.UnixMilli(first).In(loc).Format(time.RFC3339)
+	}
+	return cache
+}
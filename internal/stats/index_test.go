@@ -0,0 +1,317 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeTranscript 在 projects/<project>/ 下写入会话记录
+func writeTranscript(t *testing.T, claudeDir, project, name, content string, appendMode bool) {
+	t.Helper()
+	proj := filepath.Join(claudeDir, "projects", project)
+	if err := os.MkdirAll(proj, 0755); err != nil {
+		t.Fatal(err)
+	}
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendMode {
+		flag = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(filepath.Join(proj, name), flag, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const day1 = `{"type":"user","timestamp":"2026-02-25T09:10:00Z","sessionId":"s1"}
+{"type":"assistant","timestamp":"2026-02-25T09:10:05Z","sessionId":"s1","requestId":"r1","message":{"id":"m1"}}
+{"type":"assistant","timestamp":"2026-02-25T09:10:06Z","sessionId":"s1","requestId":"r1","message":{"id":"m1"}}
+{"type":"summary","timestamp":"2026-02-25T09:11:00Z","sessionId":"s1"}
+`
+
+// TestUpdateIndex_Aggregates 索引汇总消息、会话、每日活动和会话开始小时, 流式重复行去重
+func TestUpdateIndex_Aggregates(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", day1, false)
+	writeTranscript(t, claudeDir, "-p", "s2.jsonl", `{"type":"user","timestamp":"2026-02-26T14:00:00Z","sessionId":"s2"}
+`, false)
+
+	idx, err := UpdateIndex(claudeDir, dataDir)
+	if err != nil {
+		t.Fatalf("UpdateIndex() error: %v", err)
+	}
+	cache := idx.Cache(time.UTC)
+
+	if cache.TotalMessages != 3 || cache.TotalSessions != 2 {
+		t.Errorf("totals = %d msgs/%d sessions, want 3/2", cache.TotalMessages, cache.TotalSessions)
+	}
+	if len(cache.DailyActivity) != 2 || cache.DailyActivity[0].Date != "2026-02-25" ||
+		cache.DailyActivity[0].MessageCount != 2 || cache.DailyActivity[0].SessionCount != 1 ||
+		!slices.Equal(cache.DailyActivity[0].SessionIDs, []string{"s1"}) {
+		t.Errorf("DailyActivity = %+v", cache.DailyActivity)
+	}
+	if cache.HourCounts["9"] != 1 || cache.HourCounts["14"] != 1 {
+		t.Errorf("HourCounts = %v, want 9:1 14:1", cache.HourCounts)
+	}
+	if cache.FirstSessionDate != "2026-02-25T09:10:00Z" {
+		t.Errorf("FirstSessionDate = %q", cache.FirstSessionDate)
+	}
+}
+
+// TestUpdateIndex_Incremental 追加内容只读取新增部分, 末尾半行等写完再计入
+func TestUpdateIndex_Incremental(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", day1+`{"type":"user","timestamp":"2026-02-25T10:00:00Z",`, false)
+
+	if idx, _ := UpdateIndex(claudeDir, dataDir); idx.Cache(time.UTC).TotalMessages != 2 {
+		t.Fatalf("first pass: TotalMessages = %d, want 2", idx.Cache(time.UTC).TotalMessages)
+	}
+
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", `"sessionId":"s1"}
+{"type":"assistant","timestamp":"2026-02-25T10:00:05Z","sessionId":"s1","requestId":"r2","message":{"id":"m2"}}
+`, true)
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+	if got := idx.Cache(time.UTC).TotalMessages; got != 4 {
+		t.Errorf("after append: TotalMessages = %d, want 4", got)
+	}
+
+	// 索引已持久化, 再次更新不应重复计数
+	if got := loadIndex(dataDir).Cache(time.UTC).TotalMessages; got != 4 {
+		t.Errorf("reloaded index: TotalMessages = %d, want 4", got)
+	}
+}
+
+// TestUpdateIndex_DeletedFileKept 会话记录被清理后已计入的活动仍保留
+func TestUpdateIndex_DeletedFileKept(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", day1, false)
+	_, _ = UpdateIndex(claudeDir, dataDir)
+
+	if err := os.RemoveAll(filepath.Join(claudeDir, "projects")); err != nil {
+		t.Fatal(err)
+	}
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+	if got := idx.Cache(time.UTC).TotalMessages; got != 2 {
+		t.Errorf("TotalMessages = %d, want 2 after transcript cleanup", got)
+	}
+}
+
+// TestUpdateIndex_Truncated 文件变短时只重新读取该文件, 其他文件 (包括已删除的) 的活动保留
+func TestUpdateIndex_Truncated(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", day1, false)
+	writeTranscript(t, claudeDir, "-p", "s2.jsonl", `{"type":"user","timestamp":"2026-02-24T08:00:00Z","sessionId":"s2"}
+`, false)
+	_, _ = UpdateIndex(claudeDir, dataDir)
+	if err := os.Remove(filepath.Join(claudeDir, "projects", "-p", "s2.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+	writeTranscript(t, claudeDir, "-p", "s3.jsonl", `{"type":"user","timestamp":"2026-02-25T09:30:00Z","sessionId":"s3"}
+`, false)
+	_, _ = UpdateIndex(claudeDir, dataDir)
+
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", `{"type":"user","timestamp":"2026-02-25T09:10:00Z","sessionId":"s1"}
+`, false)
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+	cache := idx.Cache(time.UTC)
+	if cache.TotalMessages != 3 || cache.TotalSessions != 3 {
+		t.Errorf("totals = %d msgs/%d sessions, want 3/3 after rewrite", cache.TotalMessages, cache.TotalSessions)
+	}
+	if b := idx.Hours["2026-02-25T09:00"]; b == nil || b.Messages != 1 || len(b.Sessions) != 1 {
+		t.Errorf("slot bucket = %+v, want 1 message from rewritten s1", b)
+	}
+	if h := idx.Projects["-p"].Hours["2026-02-25T09:30"]; h == nil || h.Messages != 1 {
+		t.Errorf("project slot = %+v, want s3 kept", h)
+	}
+}
+
+// TestUpdateIndex_PruneFiles 已删除文件不再跟踪读取进度, 一个会话记录都没有时保留
+func TestUpdateIndex_PruneFiles(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", day1, false)
+	writeTranscript(t, claudeDir, "-p", "s2.jsonl", `{"type":"user","timestamp":"2026-02-26T14:00:00Z","sessionId":"s2"}
+`, false)
+	_, _ = UpdateIndex(claudeDir, dataDir)
+
+	if err := os.Remove(filepath.Join(claudeDir, "projects", "-p", "s1.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+	if len(idx.Files) != 1 || idx.Cache(time.UTC).TotalMessages != 3 {
+		t.Errorf("Files = %d, TotalMessages = %d; want 1 tracked file and 3 messages", len(idx.Files), idx.Cache(time.UTC).TotalMessages)
+	}
+
+	if err := os.RemoveAll(filepath.Join(claudeDir, "projects")); err != nil {
+		t.Fatal(err)
+	}
+	if idx, _ := UpdateIndex(claudeDir, dataDir); len(idx.Files) != 1 {
+		t.Errorf("Files = %d, want progress kept without transcripts", len(idx.Files))
+	}
+}
+
+// TestUpdateIndex_Migrate 旧版本索引保留已计入的活动, 只补建项目活动, 之后追加的内容照常计入
+func TestUpdateIndex_Migrate(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", day1, false)
+	path := filepath.Join(claudeDir, "projects", "-p", "s1.jsonl")
+	// 旧版本: 已读完 s1, 另有已删除会话记录 s0 的活动
+	old := fmt.Sprintf(`{"version":1,"files":{%q:{"offset":%d,"last_key":"m1:r1"}},
+"hours":{"2026-02-20T08":{"messages":5,"sessions":["s0"]},"2026-02-25T09":{"messages":2,"sessions":["s1"]}},
+"sessions":{"s0":1771574400000,"s1":1772010600000}}`, path, len(day1))
+	if err := os.WriteFile(filepath.Join(dataDir, indexFileName), []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", `{"type":"user","timestamp":"2026-02-25T10:00:00Z","sessionId":"s1"}
+`, true)
+
+	idx, err := UpdateIndex(claudeDir, dataDir)
+	if err != nil {
+		t.Fatalf("UpdateIndex() error: %v", err)
+	}
+	if got := idx.Cache(time.UTC).TotalMessages; got != 8 {
+		t.Errorf("TotalMessages = %d, want 8 (5 retained + 2 counted + 1 appended)", got)
+	}
+	if p := idx.Projects["-p"]; p == nil || len(p.Hours) != 2 || p.Hours["2026-02-25T09:00"] == nil || p.Hours["2026-02-25T09:00"].Messages != 2 {
+		t.Errorf("project = %+v, want backfilled 09:00 and appended 10:00", p)
+	}
+	if reloaded := loadIndex(dataDir); reloaded.Version != indexVersion || reloaded.migrated {
+		t.Errorf("reloaded version = %d, migrated = %v; want current version", reloaded.Version, reloaded.migrated)
+	}
+}
+
+// TestUpdateIndex_Corrupt 索引无法解析时从会话记录重建, 原文件改名保留
+func TestUpdateIndex_Corrupt(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", day1, false)
+	path := filepath.Join(dataDir, indexFileName)
+	if err := os.WriteFile(path, []byte(`{"version":2,"hours":{`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := UpdateIndex(claudeDir, dataDir)
+	if err != nil || idx.Cache(time.UTC).TotalMessages != 2 {
+		t.Fatalf("UpdateIndex() = %v; want rebuilt index with 2 messages", err)
+	}
+	if raw, _ := os.ReadFile(path + ".corrupt"); string(raw) != `{"version":2,"hours":{` {
+		t.Errorf("backup = %q, want the corrupt index kept", raw)
+	}
+	if got := loadIndex(dataDir); got.corrupt || got.Cache(time.UTC).TotalMessages != 2 {
+		t.Errorf("reloaded index corrupt = %v, want rebuilt index saved", got.corrupt)
+	}
+}
+
+// TestIndexCache_Timezone 日期按指定时区划分
+func TestIndexCache_Timezone(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", `{"type":"user","timestamp":"2026-02-25T20:00:00Z","sessionId":"s1"}
+`, false)
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+
+	shanghai := time.FixedZone("CST", 8*3600)
+	cache := idx.Cache(shanghai)
+	if len(cache.DailyActivity) != 1 || cache.DailyActivity[0].Date != "2026-02-26" {
+		t.Errorf("DailyActivity = %+v, want 2026-02-26", cache.DailyActivity)
+	}
+	if cache.HourCounts["4"] != 1 {
+		t.Errorf("HourCounts = %v, want local hour 4", cache.HourCounts)
+	}
+
+	// 凌晨 4 点 (本地) 的活动在起始小时为 5 时计入前一天, 时段统计不变
+	withDayStart(t, 5)
+	cache = idx.Cache(shanghai)
+	if len(cache.DailyActivity) != 1 || cache.DailyActivity[0].Date != "2026-02-25" {
+		t.Errorf("DailyActivity(day start 5) = %+v, want 2026-02-25", cache.DailyActivity)
+	}
+	if cache.HourCounts["4"] != 1 {
+		t.Errorf("HourCounts(day start 5) = %v, want local hour 4", cache.HourCounts)
+	}
+}
+
+// TestIndexCache_HalfHourZone 半小时偏移时区中, 同一 UTC 小时内跨午夜的消息分到各自日期
+func TestIndexCache_HalfHourZone(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	// +05:30: 18:20Z 为 23:50, 18:40Z 为次日 00:10
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", `{"type":"user","timestamp":"2026-02-25T18:20:00Z","sessionId":"s1"}
+{"type":"user","timestamp":"2026-02-25T18:40:00Z","sessionId":"s1"}
+`, false)
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+
+	cache := idx.Cache(time.FixedZone("IST", 5*3600+1800))
+	if len(cache.DailyActivity) != 2 || cache.DailyActivity[0].Date != "2026-02-25" || cache.DailyActivity[1].Date != "2026-02-26" {
+		t.Errorf("DailyActivity = %+v, want one message on each of 2-25 and 2-26", cache.DailyActivity)
+	}
+}
+
+// TestUpdateIndex_LegacyHourRewrite 迁移来的整小时活动在文件被重写时同样扣除
+func TestUpdateIndex_LegacyHourRewrite(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", day1, false)
+	path := filepath.Join(claudeDir, "projects", "-p", "s1.jsonl")
+	old := fmt.Sprintf(`{"version":1,"files":{%q:{"offset":%d}},"hours":{"2026-02-25T09":{"messages":2,"sessions":["s1"]}},"sessions":{"s1":1772010600000}}`, path, len(day1))
+	if err := os.WriteFile(filepath.Join(dataDir, indexFileName), []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = UpdateIndex(claudeDir, dataDir)
+
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", `{"type":"user","timestamp":"2026-02-25T09:10:00Z","sessionId":"s1"}
+`, false)
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+	if got := idx.Cache(time.UTC).TotalMessages; got != 1 {
+		t.Errorf("TotalMessages = %d, want 1 after rewrite", got)
+	}
+}
+
+// TestLoadCache_Fallback stats-cache.json 缺失或过期时使用会话记录索引
+func TestLoadCache_Fallback(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-p", "s1.jsonl", day1, false)
+	now := time.Now()
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+
+	cache, err := LoadCache(claudeDir, idx, now)
+	if err != nil || cache == nil || cache.TotalMessages != 2 {
+		t.Fatalf("missing stats-cache: LoadCache() = %+v, %v; want index data", cache, err)
+	}
+
+	statsPath := filepath.Join(claudeDir, "stats-cache.json")
+	if err := os.WriteFile(statsPath, []byte(`{"totalMessages":99}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cache, _ := LoadCache(claudeDir, idx, now); cache.TotalMessages != 99 {
+		t.Errorf("fresh stats-cache: TotalMessages = %d, want 99", cache.TotalMessages)
+	}
+
+	old := now.Add(-2 * staleAfter)
+	if err := os.Chtimes(statsPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if cache, _ := LoadCache(claudeDir, idx, now); cache.TotalMessages != 2 {
+		t.Errorf("stale stats-cache: TotalMessages = %d, want index data 2", cache.TotalMessages)
+	}
+}
+
+// TestLoadCache_StaleWithoutTranscripts 无会话记录时仍使用过期的 stats-cache.json
+func TestLoadCache_StaleWithoutTranscripts(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	statsPath := filepath.Join(claudeDir, "stats-cache.json")
+	if err := os.WriteFile(statsPath, []byte(`{"totalMessages":99}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleAfter)
+	if err := os.Chtimes(statsPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+	cache, err := LoadCache(claudeDir, idx, time.Now())
+	if err != nil || cache == nil || cache.TotalMessages != 99 {
+		t.Errorf("LoadCache() = %+v, %v; want stale stats-cache", cache, err)
+	}
+}
@@ -0,0 +1,124 @@
+package stats
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/pricing"
+)
+
+// ModelStats 单个模型的用量
+type ModelStats struct {
+	Model      string  `json:"model"` // model.id
+	Requests   int     `json:"requests"`
+	Input      int64   `json:"input_tokens"`
+	Output     int64   `json:"output_tokens"`
+	CacheWrite int64   `json:"cache_write_tokens"`
+	CacheRead  int64   `json:"cache_read_tokens"`
+	Cost       float64 `json:"cost"`   // 按价格表估算的 API 等价成本 (USD)
+	Priced     bool    `json:"priced"` // 价格表中是否有该模型, 否则 Cost 为 0
+}
+
+// Tokens 四类 token 之和
+func (m ModelStats) Tokens() int64 {
+	return m.Input + m.Output + m.CacheWrite + m.CacheRead
+}
+
+// ModelList 按模型汇总日期区间内的用量
+// Parameters:
+//   - since: 开始日期 (含), 零值表示不限
+//   - until: 结束日期 (含), 零值表示不限
+//   - loc: 日期划分使用的时区
+//   - prices: 用户价格表, 可为 nil
+//
+// Return:
+//   - []ModelStats: 按成本降序的模型用量, 成本相同时按请求数降序
+func (idx *Index) ModelList(since, until time.Time, loc *time.Location, prices map[string]pricing.Price) []ModelStats {
+	from, to := "", ""
+	if !since.IsZero() {
+		from = truncateToDate(since).Format(dateLayout)
+	}
+	if !until.IsZero() {
+		to = truncateToDate(until).Format(dateLayout)
+	}
+
+	byModel := make(map[string]*ModelStats)
+	for _, p := range idx.Projects {
+		for slot, h := range p.Hours {
+			if len(h.Models) == 0 {
+				continue
+			}
+			at, err := parseSlot(slot)
+			if err != nil {
+				continue
+			}
+			date := civilDate(at.In(loc)).Format(dateLayout)
+			if (from != "" && date < from) || (to != "" && date > to) {
+				continue
+			}
+			for model, u := range h.Models {
+				addModel(byModel, model, u)
+			}
+		}
+	}
+	return modelList(byModel, prices)
+}
+
+// TranscriptModels 按模型汇总单个会话记录文件计入的用量
+// Parameters:
+//   - path: 会话记录文件路径
+//   - prices: 用户价格表, 可为 nil
+//
+// Return:
+//   - []ModelStats: 按成本降序的模型用量, 文件未被索引时返回 nil
+func (idx *Index) TranscriptModels(path string, prices map[string]pricing.Price) []ModelStats {
+	f, ok := idx.Files[filepath.Clean(path)]
+	if !ok {
+		return nil
+	}
+	byModel := make(map[string]*ModelStats)
+	for _, h := range f.Hours {
+		for model, u := range h.Models {
+			addModel(byModel, model, u)
+		}
+	}
+	return modelList(byModel, prices)
+}
+
+// addModel 累加一个模型的用量
+func addModel(byModel map[string]*ModelStats, model string, u *modelUsage) {
+	m := byModel[model]
+	if m == nil {
+		m = &ModelStats{Model: model}
+		byModel[model] = m
+	}
+	m.Requests += u.Requests
+	m.Input += u.Input
+	m.Output += u.Output
+	m.CacheWrite += u.CacheWrite
+	m.CacheRead += u.CacheRead
+}
+
+// modelList 计价并按成本降序排列, 成本相同时按请求数降序
+func modelList(byModel map[string]*ModelStats, prices map[string]pricing.Price) []ModelStats {
+	list := make([]ModelStats, 0, len(byModel))
+	for _, m := range byModel {
+		if price, ok := pricing.Lookup(m.Model, prices); ok {
+			m.Cost = price.Cost(m.Input, m.Output, m.CacheWrite, m.CacheRead)
+			m.Priced = true
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		return a.Model < b.Model
+	})
+	return list
+}
@@ -0,0 +1,90 @@
+package stats
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/pricing"
+)
+
+const modelMix = `{"type":"assistant","timestamp":"2026-02-25T09:00:00Z","sessionId":"s","requestId":"r1","message":{"id":"m1","model":"claude-opus-4-1-20250805","usage":{"input_tokens":1000,"output_tokens":1000}}}
+{"type":"assistant","timestamp":"2026-02-26T09:00:00Z","sessionId":"s","requestId":"r2","message":{"id":"m2","model":"claude-sonnet-4-20250514","usage":{"input_tokens":1000000}}}
+{"type":"assistant","timestamp":"2026-02-26T09:00:00Z","sessionId":"s","requestId":"r2","message":{"id":"m2","model":"claude-sonnet-4-20250514","usage":{"input_tokens":1000000}}}
+{"type":"assistant","timestamp":"2026-02-26T10:00:00Z","sessionId":"s","requestId":"r3","message":{"id":"m3","model":"claude-sonnet-4-20250514","usage":{"cache_read_input_tokens":1000000}}}
+{"type":"assistant","timestamp":"2026-02-26T10:00:00Z","sessionId":"s","requestId":"r4","message":{"id":"m4","model":"<synthetic>","usage":{"input_tokens":0}}}
+{"type":"assistant","timestamp":"2026-02-26T11:00:00Z","sessionId":"s","requestId":"r5","message":{"id":"m5","model":"my-local-model","usage":{"output_tokens":10}}}
+`
+
+// TestModelList 按模型汇总请求、token 与成本, 去重流式行并忽略 <synthetic>
+func TestModelList(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-a", "s.jsonl", modelMix, false)
+
+	idx, err := UpdateIndex(claudeDir, dataDir)
+	if err != nil {
+		t.Fatalf("UpdateIndex() error: %v", err)
+	}
+	list := idx.ModelList(time.Time{}, time.Time{}, time.UTC, nil)
+	if len(list) != 3 {
+		t.Fatalf("ModelList() = %+v, want 3 models", list)
+	}
+	sonnet := list[0]
+	// 1M 输入 $3 + 1M 缓存读取 $0.3
+	if sonnet.Model != "claude-sonnet-4-20250514" || sonnet.Requests != 2 || math.Abs(sonnet.Cost-3.3) > 1e-9 || sonnet.Tokens() != 2000000 {
+		t.Errorf("sonnet = %+v", sonnet)
+	}
+	if opus := list[1]; opus.Requests != 1 || !opus.Priced || math.Abs(opus.Cost-0.09) > 1e-9 {
+		t.Errorf("opus = %+v", opus)
+	}
+	if local := list[2]; local.Model != "my-local-model" || local.Priced || local.Cost != 0 {
+		t.Errorf("local = %+v", local)
+	}
+
+	// 用户价格表为未知模型计价
+	list = idx.ModelList(time.Time{}, time.Time{}, time.UTC, map[string]pricing.Price{"my-local": {Output: 1000000}})
+	for _, m := range list {
+		if m.Model == "my-local-model" && (!m.Priced || m.Cost != 10) {
+			t.Errorf("priced local = %+v, want $10", m)
+		}
+	}
+}
+
+// TestModelList_DateRange 按统计日筛选
+func TestModelList_DateRange(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-a", "s.jsonl", modelMix, false)
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+
+	today := Today(testNow)
+	list := idx.ModelList(today, today, time.UTC, nil)
+	if len(list) != 2 || list[0].Model != "claude-sonnet-4-20250514" {
+		t.Errorf("today = %+v, want sonnet and local model", list)
+	}
+	list = idx.ModelList(time.Time{}, today.AddDate(0, 0, -1), time.UTC, nil)
+	if len(list) != 1 || list[0].Model != "claude-opus-4-1-20250805" {
+		t.Errorf("until yesterday = %+v, want opus only", list)
+	}
+	// 起始小时为 10 时, 2-26 09:00 的请求计入 2-25
+	withDayStart(t, 10)
+	list = idx.ModelList(time.Time{}, today.AddDate(0, 0, -1), time.UTC, nil)
+	if len(list) != 2 {
+		t.Errorf("until yesterday (day start 10) = %+v, want opus and sonnet", list)
+	}
+}
+
+// TestModelList_Rewritten 文件被重写时扣除其旧的模型用量
+func TestModelList_Rewritten(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-a", "s.jsonl", modelMix, false)
+	_, _ = UpdateIndex(claudeDir, dataDir)
+
+	lines := strings.SplitAfter(modelMix, "\n")
+	writeTranscript(t, claudeDir, "-a", "s.jsonl", lines[0], false)
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+	list := idx.ModelList(time.Time{}, time.Time{}, time.UTC, nil)
+	if len(list) != 1 || list[0].Model != "claude-opus-4-1-20250805" || list[0].Requests != 1 || list[0].Tokens() != 2000 {
+		t.Errorf("after rewrite = %+v, want opus only", list)
+	}
+}
@@ -0,0 +1,155 @@
+package stats
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/pricing"
+)
+
+// 项目排序方式
+const (
+	ProjectSortMessages = "messages"
+	ProjectSortSessions = "sessions"
+	ProjectSortCost     = "cost"
+	ProjectSortRecent   = "recent"
+)
+
+// ProjectStats 单个项目的统计
+type ProjectStats struct {
+	Key           string  `json:"key"` // projects/ 下的目录名
+	Dir           string  `json:"dir"` // 项目目录, 会话记录中没有工作目录时为空
+	Sessions      int     `json:"sessions"`
+	Messages      int     `json:"messages"`
+	Tokens        int64   `json:"tokens"`
+	Cost          float64 `json:"cost"` // 按价格表估算的 API 等价成本 (USD), 未知模型不计
+	ActiveDays    int     `json:"active_days"`
+	Streak        int     `json:"streak"` // 当前连续活跃 (按连续活跃规则)
+	LongestStreak Streak  `json:"longest_streak"`
+	LastActive    string  `json:"last_active"` // 最后活跃日期
+}
+
+// Name 项目显示名称: 项目目录的最后一级, 无目录时为目录名
+func (p ProjectStats) Name() string {
+	if p.Dir != "" {
+		return filepath.Base(p.Dir)
+	}
+	return p.Key
+}
+
+// ProjectList 汇总各项目统计
+// Parameters:
+//   - now: 当前时间, 其时区决定日期划分
+//   - prices: 用户价格表, 可为 nil
+//
+// Return:
+//   - []ProjectStats: 按消息数降序的项目统计
+func (idx *Index) ProjectList(now time.Time, prices map[string]pricing.Price) []ProjectStats {
+	list := make([]ProjectStats, 0, len(idx.Projects))
+	for key, b := range idx.Projects {
+		p := ProjectStats{Key: key, Dir: b.Cwd, Sessions: len(b.Sessions)}
+		days := make(map[string]int)
+		for slot, h := range b.Hours {
+			at, err := parseSlot(slot)
+			if err != nil {
+				continue
+			}
+			p.Messages += h.Messages
+			days[civilDate(at.In(now.Location())).Format(dateLayout)] += h.Messages
+			for model, t := range h.Models {
+				p.Tokens += t.Total()
+				if price, ok := pricing.Lookup(model, prices); ok {
+					p.Cost += price.Cost(t.Input, t.Output, t.CacheWrite, t.CacheRead)
+				}
+			}
+		}
+
+		var dates []string
+		for date, n := range days {
+			p.LastActive = max(p.LastActive, date)
+			if streakPolicy.counts(n) {
+				dates = append(dates, date)
+			}
+		}
+		p.ActiveDays = len(days)
+		w := walkStreaks(dates, civilDate(now))
+		p.Streak = w.current
+		for _, run := range w.runs {
+			if run.Days > p.LongestStreak.Days {
+				p.LongestStreak = run
+			}
+		}
+		list = append(list, p)
+	}
+	SortProjects(list, ProjectSortMessages)
+	return list
+}
+
+// SortProjects 按指定方式降序排序, 相同时按目录名升序; 未知方式按消息数
+// Parameters:
+//   - list: 项目统计
+//   - by: 排序方式 (messages/sessions/cost/recent)
+func SortProjects(list []ProjectStats, by string) {
+	key := func(p ProjectStats) float64 {
+		switch by {
+		case ProjectSortSessions:
+			return float64(p.Sessions)
+		case ProjectSortCost:
+			return p.Cost
+		}
+		return float64(p.Messages)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if by == ProjectSortRecent && a.LastActive != b.LastActive {
+			return a.LastActive > b.LastActive
+		}
+		if ka, kb := key(a), key(b); by != ProjectSortRecent && ka != kb {
+			return ka > kb
+		}
+		return a.Key < b.Key
+	})
+}
+
+// FindProject 查找工作目录所属的项目: 优先匹配编码后的目录名, 其次取项目目录为其最长前缀者
+// Parameters:
+//   - list: 项目统计
+//   - dir: 当前工作目录
+//
+// Return:
+//   - *ProjectStats: 所属项目, 找不到时返回 nil
+func FindProject(list []ProjectStats, dir string) *ProjectStats {
+	if dir == "" {
+		return nil
+	}
+	dir = filepath.Clean(dir)
+	key := projectKey(dir)
+	var best *ProjectStats
+	bestLen := 0
+	for i := range list {
+		p := &list[i]
+		if p.Key == key {
+			return p
+		}
+		if p.Dir == "" {
+			continue
+		}
+		root := filepath.Clean(p.Dir)
+		if (dir == root || strings.HasPrefix(dir, root+string(filepath.Separator))) && len(root) > bestLen {
+			best, bestLen = p, len(root)
+		}
+	}
+	return best
+}
+
+// projectKey 按 Claude Code 的规则将目录编码为 projects/ 下的目录名 (非字母数字替换为 "-")
+func projectKey(dir string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '-'
+	}, dir)
+}
@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+const repoA = `{"type":"user","timestamp":"2026-02-25T09:00:00Z","sessionId":"a1","cwd":"/home/me/repo-a"}
+{"type":"assistant","timestamp":"2026-02-25T09:00:05Z","sessionId":"a1","cwd":"/home/me/repo-a/sub","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":1000000,"output_tokens":100000}}}
+{"type":"assistant","timestamp":"2026-02-25T09:00:06Z","sessionId":"a1","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":1000000,"output_tokens":100000}}}
+{"type":"user","timestamp":"2026-02-26T08:00:00Z","sessionId":"a2","cwd":"/home/me/repo-a"}
+{"type":"assistant","timestamp":"2026-02-26T08:00:05Z","sessionId":"a2","requestId":"r2","message":{"id":"m2","model":"unknown-model","usage":{"input_tokens":500}}}
+`
+
+const repoB = `{"type":"user","timestamp":"2026-02-20T09:00:00Z","sessionId":"b1","cwd":"/home/me/repo-b"}
+`
+
+// TestProjectList 按项目汇总会话、消息、成本与连续活跃
+func TestProjectList(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-home-me-repo-a", "a1.jsonl", repoA, false)
+	writeTranscript(t, claudeDir, "-home-me-repo-b", "b1.jsonl", repoB, false)
+
+	idx, err := UpdateIndex(claudeDir, dataDir)
+	if err != nil {
+		t.Fatalf("UpdateIndex() error: %v", err)
+	}
+	list := idx.ProjectList(testNow, nil)
+	if len(list) != 2 || list[0].Key != "-home-me-repo-a" {
+		t.Fatalf("ProjectList() = %+v, want repo-a first", list)
+	}
+	a := list[0]
+	if a.Dir != "/home/me/repo-a" || a.Name() != "repo-a" {
+		t.Errorf("Dir = %q, Name = %q", a.Dir, a.Name())
+	}
+	if a.Sessions != 2 || a.Messages != 4 || a.ActiveDays != 2 || a.Streak != 2 || a.LastActive != "2026-02-26" {
+		t.Errorf("repo-a = %+v", a)
+	}
+	// sonnet: 1M 输入 $3 + 0.1M 输出 $1.5, 重复行与未知模型不计费
+	if math.Abs(a.Cost-4.5) > 1e-9 || a.Tokens != 1100500 {
+		t.Errorf("repo-a cost = %.4f tokens = %d, want 4.5 / 1100500", a.Cost, a.Tokens)
+	}
+	if b := list[1]; b.Streak != 0 || b.LongestStreak.Days != 1 || b.Messages != 1 {
+		t.Errorf("repo-b = %+v", b)
+	}
+}
+
+func TestSortProjects(t *testing.T) {
+	list := []ProjectStats{
+		{Key: "a", Messages: 10, Sessions: 5, Cost: 1, LastActive: "2026-02-01"},
+		{Key: "b", Messages: 20, Sessions: 1, Cost: 3, LastActive: "2026-02-20"},
+		{Key: "c", Messages: 20, Sessions: 9, Cost: 2, LastActive: "2026-02-10"},
+	}
+	tests := map[string]string{
+		ProjectSortMessages: "bca",
+		ProjectSortSessions: "cab",
+		ProjectSortCost:     "bca",
+		ProjectSortRecent:   "bca",
+		"bogus":             "bca",
+	}
+	for by, want := range tests {
+		SortProjects(list, by)
+		got := ""
+		for _, p := range list {
+			got += p.Key
+		}
+		if got != want {
+			t.Errorf("SortProjects(%s) = %s, want %s", by, got, want)
+		}
+	}
+}
+
+func TestFindProject(t *testing.T) {
+	list := []ProjectStats{
+		{Key: "-home-me-repo", Dir: "/home/me/repo"},
+		{Key: "-home-me-repo-web", Dir: "/home/me/repo/web"},
+		{Key: "-home-me-other-x", Dir: ""},
+	}
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"/home/me/repo", "-home-me-repo"},
+		{"/home/me/repo/web/src", "-home-me-repo-web"},
+		{"/home/me/repo/api", "-home-me-repo"},
+		{"/home/me/other.x", "-home-me-other-x"}, // 按编码后的目录名匹配
+		{"/home/me/repository", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got := FindProject(list, tt.dir)
+		if (got == nil && tt.want != "") || (got != nil && got.Key != tt.want) {
+			t.Errorf("FindProject(%q) = %+v, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+// TestProjectList_Timezone 项目日期按 now 的时区划分
+func TestProjectList_Timezone(t *testing.T) {
+	claudeDir, dataDir := t.TempDir(), t.TempDir()
+	writeTranscript(t, claudeDir, "-r", "s.jsonl", `{"type":"user","timestamp":"2026-02-25T20:00:00Z","sessionId":"s"}
+`, false)
+	now := time.Date(2026, 2, 26, 10, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	idx, _ := UpdateIndex(claudeDir, dataDir)
+	list := idx.ProjectList(now, nil)
+	if len(list) != 1 || list[0].LastActive != "2026-02-26" || list[0].Streak != 1 {
+		t.Errorf("ProjectList() = %+v, want active on 2026-02-26", list)
+	}
+}
@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/model"
+)
+
+// 纪录类型, 用于 StatsInfo.NewRecords
+const (
+	RecordStreak   = "streak"
+	RecordMessages = "messages"
+	RecordSessions = "sessions"
+)
+
+// computeRecords 根据每日活动计算历史纪录, 并找出今天刷新的纪录
+// 只有今天超过其他日期的最高值才算刷新 (首个活跃日不算); 最长连续按连续活跃规则计算
+// Parameters:
+//   - activity: 每日活动
+//   - todayDate: 今天的日期 (UTC 零点)
+//
+// Return:
+//   - model.Records: 历史纪录 (含今天)
+//   - []string: 今天刷新的纪录类型
+func computeRecords(activity []model.DailyActivity, todayDate time.Time) (model.Records, []string) {
+	today := todayDate.Format(dateLayout)
+	byDate := make(map[string]model.DailyActivity, len(activity))
+	for _, d := range activity {
+		prev := byDate[d.Date]
+		prev.Date = d.Date
+		prev.MessageCount += d.MessageCount
+		prev.SessionCount += d.SessionCount
+		byDate[d.Date] = prev
+	}
+
+	dates := make([]string, 0, len(byDate))
+	var streakDates []string
+	for date, d := range byDate {
+		dates = append(dates, date)
+		if streakPolicy.counts(d.MessageCount) {
+			streakDates = append(streakDates, date)
+		}
+	}
+	sort.Strings(dates)
+
+	// 按日期升序遍历, 同值时保留较早的日期; before 为不含今天的纪录
+	var rec, before model.Records
+	for _, date := range dates {
+		d := byDate[date]
+		if d.MessageCount > rec.MostMessages {
+			rec.MostMessages, rec.MostMessagesDate = d.MessageCount, date
+		}
+		if d.SessionCount > rec.MostSessions {
+			rec.MostSessions, rec.MostSessionsDate = d.SessionCount, date
+		}
+		if date != today {
+			before.MostMessages = max(before.MostMessages, d.MessageCount)
+			before.MostSessions = max(before.MostSessions, d.SessionCount)
+		}
+	}
+
+	var current Streak
+	for _, run := range walkStreaks(streakDates, todayDate).runs {
+		if run.Days > rec.LongestStreak {
+			rec.LongestStreak, rec.LongestStreakStart, rec.LongestStreakEnd = run.Days, run.Start, run.End
+		}
+		if run.End == today {
+			current = run
+		} else if run.Days > before.LongestStreak {
+			before.LongestStreak = run.Days
+		}
+	}
+
+	var broken []string
+	if current.Days > 0 && before.LongestStreak > 0 && current.Days > before.LongestStreak {
+		broken = append(broken, RecordStreak)
+	}
+	if t, ok := byDate[today]; ok {
+		if before.MostMessages > 0 && t.MessageCount > before.MostMessages {
+			broken = append(broken, RecordMessages)
+		}
+		if before.MostSessions > 0 && t.SessionCount > before.MostSessions {
+			broken = append(broken, RecordSessions)
+		}
+	}
+	return rec, broken
+}
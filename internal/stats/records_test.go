@@ -0,0 +1,59 @@
+package stats
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nyan-statusline-cc/internal/model"
+)
+
+// TestComputeRecords 最长连续 (含日期)、单日最多消息/会话, 同值取较早日期
+func TestComputeRecords(t *testing.T) {
+	activity := []model.DailyActivity{
+		{Date: "2026-02-01", MessageCount: 40, SessionCount: 2},
+		{Date: "2026-02-02", MessageCount: 10, SessionCount: 5},
+		{Date: "2026-02-03", MessageCount: 5, SessionCount: 1},
+		{Date: "2026-02-10", MessageCount: 40, SessionCount: 1},
+		{Date: "2026-02-25", MessageCount: 3, SessionCount: 1},
+		{Date: "2026-02-26", MessageCount: 2, SessionCount: 1},
+	}
+	rec, broken := computeRecords(activity, civilDate(testNow))
+
+	want := model.Records{
+		LongestStreak: 3, LongestStreakStart: "2026-02-01", LongestStreakEnd: "2026-02-03",
+		MostMessages: 40, MostMessagesDate: "2026-02-01",
+		MostSessions: 5, MostSessionsDate: "2026-02-02",
+	}
+	if rec != want {
+		t.Errorf("computeRecords() = %+v, want %+v", rec, want)
+	}
+	if len(broken) != 0 {
+		t.Errorf("NewRecords = %v, want none", broken)
+	}
+}
+
+// TestComputeRecords_NewToday 今天超过其他日期时标记刷新纪录
+func TestComputeRecords_NewToday(t *testing.T) {
+	activity := []model.DailyActivity{
+		{Date: "2026-02-10", MessageCount: 20, SessionCount: 3},
+		{Date: "2026-02-24", MessageCount: 5, SessionCount: 1},
+		{Date: "2026-02-25", MessageCount: 5, SessionCount: 1},
+		{Date: "2026-02-26", MessageCount: 21, SessionCount: 2},
+	}
+	rec, broken := computeRecords(activity, civilDate(testNow))
+
+	if !slices.Equal(broken, []string{RecordStreak, RecordMessages}) {
+		t.Errorf("NewRecords = %v, want [streak messages]", broken)
+	}
+	if rec.MostMessagesDate != "2026-02-26" || rec.LongestStreakEnd != "2026-02-26" {
+		t.Errorf("records = %+v, want today as best", rec)
+	}
+}
+
+// TestComputeRecords_FirstDay 首个活跃日不算刷新纪录
+func TestComputeRecords_FirstDay(t *testing.T) {
+	activity := []model.DailyActivity{{Date: "2026-02-26", MessageCount: 10, SessionCount: 1}}
+	if _, broken := computeRecords(activity, civilDate(testNow)); len(broken) != 0 {
+		t.Errorf("NewRecords = %v, want none on first day", broken)
+	}
+}
@@ -0,0 +1,175 @@
+package stats
+
+import (
+	"slices"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/model"
+)
+
+const dateLayout = "2006-01-02"
+
+// reportDays 未指定 --since 时报告覆盖的天数 (约一年)
+const reportDays = 365
+
+// Streak 一段连续活跃区间
+type Streak struct {
+	Days  int    `json:"days"`
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// DayCount 单日消息与会话数
+type DayCount struct {
+	Date     string `json:"date"`
+	Messages int    `json:"messages"`
+	Sessions int    `json:"sessions"`
+}
+
+// WeekTrend 最近 7 天与之前 7 天的消息数对比
+type WeekTrend struct {
+	ThisWeek  int     `json:"this_week"`
+	LastWeek  int     `json:"last_week"`
+	ChangePct float64 `json:"change_pct"` // 环比变化百分比, 上周为 0 时为 0
+}
+
+// Report stats 子命令的完整统计报告
+type Report struct {
+	Since string `json:"since"`
+	Until string `json:"until"`
+
+	// 全部历史 (来自 ComputeStatsInfo)
+	CodingDays    int           `json:"coding_days"`
+	TotalSessions int           `json:"total_sessions"`
+	TotalMessages int           `json:"total_messages"`
+	CurrentStreak int           `json:"current_streak"`
+	Records       model.Records `json:"records"`
+
+	// 区间内统计
+	Messages             int        `json:"messages"`
+	Sessions             int        `json:"sessions"`          // 不重复会话数; SessionsDistinct 为 false 时为每日会话数之和
+	SessionsDistinct     bool       `json:"sessions_distinct"` // 数据来源提供会话 ID, 跨天会话只计一次
+	ActiveDays           int        `json:"active_days"`
+	LongestStreak        Streak     `json:"longest_streak"` // 按连续活跃规则计算
+	BestDay              DayCount   `json:"best_day"`
+	AvgMessagesPerActive float64    `json:"avg_messages_per_active_day"`
+	AvgSessionsPerActive float64    `json:"avg_sessions_per_active_day"`
+	Trend                WeekTrend  `json:"week_trend"`
+	HourCounts           [24]int    `json:"hour_counts"` // 全部历史的会话开始时段
+	Days                 []DayCount `json:"days"`        // 区间内每一天 (含无活动的日期)
+}
+
+// BuildReport 根据统计缓存生成区间报告
+// Parameters:
+//   - cache: 统计缓存
+//   - since: 区间开始日期, 零值表示 until 前约一年
+//   - until: 区间结束日期, 零值表示今天
+//   - now: 当前时间
+//
+// Return:
+//   - *Report: 统计报告
+func BuildReport(cache *model.StatsCache, since, until, now time.Time) *Report {
+	if cache == nil {
+		cache = &model.StatsCache{}
+	}
+	// 显式指定的日期按日历日期处理, 默认的 "今天" 与缓存日期按同一规则划分
+	if until.IsZero() {
+		until = cacheDate(cache, now)
+	} else {
+		until = truncateToDate(until)
+	}
+	if since.IsZero() {
+		since = until.AddDate(0, 0, -(reportDays - 1))
+	} else {
+		since = truncateToDate(since)
+	}
+
+	info := ComputeStatsInfo(cache, now)
+	r := &Report{
+		Since:         since.Format(dateLayout),
+		Until:         until.Format(dateLayout),
+		CodingDays:    info.CodingDays,
+		TotalSessions: info.TotalSessions,
+		TotalMessages: info.TotalMessages,
+		CurrentStreak: info.Streak,
+		Records:       info.Records,
+		HourCounts:    info.HourCounts,
+	}
+
+	byDate := make(map[string]model.DailyActivity, len(cache.DailyActivity))
+	for _, d := range cache.DailyActivity {
+		prev := byDate[d.Date]
+		byDate[d.Date] = model.DailyActivity{
+			Date:         d.Date,
+			MessageCount: prev.MessageCount + d.MessageCount,
+			SessionCount: prev.SessionCount + d.SessionCount,
+			SessionIDs:   slices.Concat(prev.SessionIDs, d.SessionIDs),
+		}
+	}
+
+	// stats-cache.json 只有每日会话数, 会话 ID 齐全时才能去重
+	r.SessionsDistinct = true
+	sessions := make(map[string]struct{})
+	daySessions := 0
+
+	var active []string
+	for day := since; !day.After(until); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		a := byDate[date]
+		dc := DayCount{Date: date, Messages: a.MessageCount, Sessions: a.SessionCount}
+		r.Days = append(r.Days, dc)
+
+		if dc.Messages == 0 && dc.Sessions == 0 {
+			continue
+		}
+		if len(a.SessionIDs) < a.SessionCount {
+			r.SessionsDistinct = false
+		}
+		for _, id := range a.SessionIDs {
+			sessions[id] = struct{}{}
+		}
+		if streakPolicy.counts(dc.Messages) {
+			active = append(active, date)
+		}
+		r.ActiveDays++
+		r.Messages += dc.Messages
+		daySessions += dc.Sessions
+		if dc.Messages > r.BestDay.Messages {
+			r.BestDay = dc
+		}
+	}
+	for _, run := range walkStreaks(active, until).runs {
+		if run.Days > r.LongestStreak.Days {
+			r.LongestStreak = run
+		}
+	}
+
+	r.Sessions = daySessions
+	if r.SessionsDistinct {
+		r.Sessions = len(sessions)
+	}
+
+	if r.ActiveDays > 0 {
+		r.AvgMessagesPerActive = float64(r.Messages) / float64(r.ActiveDays)
+		r.AvgSessionsPerActive = float64(daySessions) / float64(r.ActiveDays)
+	}
+	r.Trend = weekTrend(byDate, until)
+	return r
+}
+
+// weekTrend 计算截至 until 的最近 7 天与之前 7 天的消息数对比
+func weekTrend(byDate map[string]model.DailyActivity, until time.Time) WeekTrend {
+	var t WeekTrend
+	for i := range 14 {
+		n := byDate[until.AddDate(0, 0, -i).Format(dateLayout)].MessageCount
+		if i < 7 {
+			t.ThisWeek += n
+		} else {
+			t.LastWeek += n
+		}
+	}
+	if t.LastWeek > 0 {
+		t.ChangePct = float64(t.ThisWeek-t.LastWeek) / float64(t.LastWeek) * 100
+	}
+	return t
+}
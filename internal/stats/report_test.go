@@ -0,0 +1,107 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/model"
+)
+
+func reportCache() *model.StatsCache {
+	return &model.StatsCache{
+		FirstSessionDate: "2026-01-01T09:00:00Z",
+		TotalSessions:    20,
+		TotalMessages:    300,
+		DailyActivity: []model.DailyActivity{
+			{Date: "2026-02-10", MessageCount: 10, SessionCount: 1},
+			{Date: "2026-02-11", MessageCount: 30, SessionCount: 2},
+			{Date: "2026-02-12", MessageCount: 20, SessionCount: 1},
+			{Date: "2026-02-20", MessageCount: 5, SessionCount: 1},
+			{Date: "2026-02-25", MessageCount: 15, SessionCount: 1},
+			{Date: "2026-02-26", MessageCount: 25, SessionCount: 2},
+		},
+		HourCounts: map[string]int{"10": 4},
+	}
+}
+
+// TestBuildReport 区间统计: 活跃天数、最长连续、最佳日、均值、环比
+func TestBuildReport(t *testing.T) {
+	r := BuildReport(reportCache(), time.Time{}, time.Time{}, testNow)
+
+	if r.Until != "2026-02-26" || r.Since != "2025-02-27" || len(r.Days) != reportDays {
+		t.Errorf("range = %s ~ %s (%d days), want one year ending today", r.Since, r.Until, len(r.Days))
+	}
+	if r.ActiveDays != 6 || r.Messages != 105 || r.Sessions != 8 {
+		t.Errorf("totals = %d days/%d msgs/%d sessions, want 6/105/8", r.ActiveDays, r.Messages, r.Sessions)
+	}
+	if r.SessionsDistinct {
+		t.Error("stats-cache.json has no session IDs, Sessions should be a sum of daily counts")
+	}
+	if r.LongestStreak != (Streak{Days: 3, Start: "2026-02-10", End: "2026-02-12"}) {
+		t.Errorf("LongestStreak = %+v", r.LongestStreak)
+	}
+	if r.CurrentStreak != 2 || r.TotalMessages != 300 || r.CodingDays != 57 {
+		t.Errorf("all-time = streak %d, msgs %d, days %d", r.CurrentStreak, r.TotalMessages, r.CodingDays)
+	}
+	if r.BestDay.Date != "2026-02-11" || r.BestDay.Messages != 30 {
+		t.Errorf("BestDay = %+v", r.BestDay)
+	}
+	if math.Abs(r.AvgMessagesPerActive-17.5) > 1e-9 {
+		t.Errorf("AvgMessagesPerActive = %f, want 17.5", r.AvgMessagesPerActive)
+	}
+	// 最近 7 天 (02-20 ~ 02-26) 45 条, 之前 7 天 (02-13 ~ 02-19) 0 条
+	if r.Trend.ThisWeek != 45 || r.Trend.LastWeek != 0 || r.Trend.ChangePct != 0 {
+		t.Errorf("Trend = %+v", r.Trend)
+	}
+	if r.HourCounts[10] != 4 {
+		t.Errorf("HourCounts[10] = %d, want 4", r.HourCounts[10])
+	}
+}
+
+// TestBuildReport_Range --since/--until 只统计区间内的日期, 环比以 until 为准
+func TestBuildReport_Range(t *testing.T) {
+	since := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)
+	r := BuildReport(reportCache(), since, until, testNow)
+
+	if len(r.Days) != 10 || r.Days[0].Date != "2026-02-11" {
+		t.Fatalf("Days = %d starting %s, want 10 starting 2026-02-11", len(r.Days), r.Days[0].Date)
+	}
+	if r.Messages != 55 || r.ActiveDays != 3 {
+		t.Errorf("Messages = %d, ActiveDays = %d, want 55/3", r.Messages, r.ActiveDays)
+	}
+	if r.LongestStreak.Days != 2 || r.LongestStreak.Start != "2026-02-11" {
+		t.Errorf("LongestStreak = %+v", r.LongestStreak)
+	}
+	// 02-14 ~ 02-20: 5 条; 02-07 ~ 02-13: 60 条
+	if r.Trend.ThisWeek != 5 || r.Trend.LastWeek != 60 || math.Abs(r.Trend.ChangePct+91.666) > 0.01 {
+		t.Errorf("Trend = %+v", r.Trend)
+	}
+}
+
+// TestBuildReport_DistinctSessions 有会话 ID 时跨天的会话只计一次, 日均仍按每日会话数
+func TestBuildReport_DistinctSessions(t *testing.T) {
+	cache := &model.StatsCache{
+		DailyActivity: []model.DailyActivity{
+			{Date: "2026-02-24", MessageCount: 10, SessionCount: 2, SessionIDs: []string{"a", "b"}},
+			{Date: "2026-02-25", MessageCount: 5, SessionCount: 1, SessionIDs: []string{"b"}},
+			{Date: "2026-02-26", MessageCount: 8, SessionCount: 2, SessionIDs: []string{"b", "c"}},
+		},
+	}
+	r := BuildReport(cache, time.Time{}, time.Time{}, testNow)
+	if !r.SessionsDistinct || r.Sessions != 3 {
+		t.Errorf("Sessions = %d (distinct %v), want 3 distinct", r.Sessions, r.SessionsDistinct)
+	}
+	if math.Abs(r.AvgSessionsPerActive-5.0/3) > 1e-9 {
+		t.Errorf("AvgSessionsPerActive = %f, want 5/3", r.AvgSessionsPerActive)
+	}
+}
+
+// TestBuildReport_Empty 无数据时不应 panic
+func TestBuildReport_Empty(t *testing.T) {
+	r := BuildReport(nil, time.Time{}, time.Time{}, testNow)
+	if r.ActiveDays != 0 || r.LongestStreak.Days != 0 || r.AvgMessagesPerActive != 0 {
+		t.Errorf("empty report = %+v", r)
+	}
+}
@@ -5,36 +5,80 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"time"
 
 	"github.com/nyan-statusline-cc/internal/model"
 )
 
-// GetStatsInfo 读取 stats-cache.json 并解析为统计摘要
+// dailyWindow 每日消息序列保留的天数
+const dailyWindow = 30
+
+// avgWindow 今日消息对比的基线天数 (不含今天)
+const avgWindow = 7
+
+// staleAfter stats-cache.json 超过该时长未更新视为过期, 改用会话记录索引
+const staleAfter = 24 * time.Hour
+
+// GetStatsInfo 读取统计数据并解析为统计摘要
 // Parameters:
-//   - binaryDir: 二进制文件所在目录 (stats-cache.json 同目录)
+//   - claudeDir: Claude Code 数据目录 (stats-cache.json 所在目录)
+//   - idx: 已更新的会话记录索引, 可为 nil
+//   - now: 当前时间, 其时区决定 "今天" 与每日边界
 //
 // Return:
-//   - *model.StatsInfo: 统计摘要, 文件不存在时返回 nil
+//   - *model.StatsInfo: 统计摘要, 无任何统计数据时返回 nil
 //   - error: 读取或解析错误
-func GetStatsInfo(binaryDir string) (*model.StatsInfo, error) {
-	statsPath := filepath.Join(binaryDir, "stats-cache.json")
-	data, err := os.ReadFile(statsPath)
+func GetStatsInfo(claudeDir string, idx *Index, now time.Time) (*model.StatsInfo, error) {
+	cache, err := LoadCache(claudeDir, idx, now)
+	if cache == nil {
+		return nil, err
+	}
+	return ComputeStatsInfo(cache, now), nil
+}
+
+// LoadCache 读取统计缓存: stats-cache.json 存在且未过期时直接使用,
+// 否则使用会话记录索引; 两者都没有数据时退回过期的 stats-cache.json
+// Parameters:
+//   - claudeDir: Claude Code 数据目录
+//   - idx: 已更新的会话记录索引 (见 UpdateIndex), 可为 nil
+//   - now: 当前时间 (判断是否过期)
+//
+// Return:
+//   - *model.StatsCache: 统计缓存, 无数据时返回 nil
+//   - error: stats-cache.json 解析错误
+func LoadCache(claudeDir string, idx *Index, now time.Time) (*model.StatsCache, error) {
+	statsPath := filepath.Join(claudeDir, "stats-cache.json")
+	var cache *model.StatsCache
+	var parseErr error
+	if fi, err := os.Stat(statsPath); err == nil {
+		cache, parseErr = readStatsCache(statsPath)
+		if cache != nil && now.Sub(fi.ModTime()) < staleAfter {
+			return cache, nil
+		}
+	}
+
+	if idx != nil && len(idx.Hours) > 0 {
+		return idx.Cache(now.Location()), nil
+	}
+	return cache, parseErr
+}
+
+// readStatsCache 解析 stats-cache.json
+func readStatsCache(path string) (*model.StatsCache, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, nil
 	}
-
 	var cache model.StatsCache
 	if err := json.Unmarshal(data, &cache); err != nil {
 		return nil, err
 	}
-
-	return ComputeStatsInfo(&cache, time.Now()), nil
+	return &cache, nil
 }
 
 // ComputeStatsInfo 根据缓存数据和当前时间计算统计摘要
+// "今天" 由 now 的时区决定, 并与缓存日期按同一规则划分 (见 cacheDate)
 // Parameters:
 //   - cache: 统计缓存数据
 //   - now: 当前时间 (用于计算天数和连续活跃)
@@ -45,6 +89,7 @@ func ComputeStatsInfo(cache *model.StatsCache, now time.Time) *model.StatsInfo {
 	if cache == nil {
 		return &model.StatsInfo{}
 	}
+	today := cacheDate(cache, now)
 
 	info := &model.StatsInfo{
 		TotalSessions: cache.TotalSessions,
@@ -53,26 +98,21 @@ func ComputeStatsInfo(cache *model.StatsCache, now time.Time) *model.StatsInfo {
 	}
 
 	// 计算使用天数 (首日也算一天, 所以 +1)
+	// FirstSessionDate 通常为 UTC, 先换算到 now 的时区再取日期
 	if cache.FirstSessionDate != "" {
 		first, err := time.Parse(time.RFC3339, cache.FirstSessionDate)
 		if err == nil {
-			firstDate := truncateToDate(first)
-			nowDate := truncateToDate(now)
-			days := int(nowDate.Sub(firstDate).Hours()/24) + 1
-			if days < 1 {
-				days = 1
-			}
-			info.CodingDays = days
+			days := daysBetween(cacheDate(cache, first.In(now.Location())), today) + 1
+			info.CodingDays = max(days, 1)
 		}
 	}
 
 	// 计算连续活跃天数
-	info.Streak = calcStreak(cache.DailyActivity, now)
+	info.Streak, info.FreezesLeft = streakStatus(cache.DailyActivity, today)
 
 	// 今日统计
-	today := now.Format("2006-01-02")
 	for _, day := range cache.DailyActivity {
-		if day.Date == today {
+		if day.Date == today.Format(dateLayout) {
 			info.TodayMessages = day.MessageCount
 			info.TodaySessions = day.SessionCount
 			break
@@ -82,9 +122,55 @@ func ComputeStatsInfo(cache *model.StatsCache, now time.Time) *model.StatsInfo {
 	// 最活跃时段: count 相同时取较小 hour, 保证确定性
 	calcPeakHour(cache.HourCounts, info)
 
+	// 历史纪录
+	info.Records, info.NewRecords = computeRecords(cache.DailyActivity, today)
+
+	// 趋势图与热力图数据
+	info.DailyMessages = dailySeries(cache.DailyActivity, today, dailyWindow)
+	for h, c := range cache.HourCounts {
+		if hour, err := strconv.Atoi(h); err == nil && hour >= 0 && hour < 24 {
+			info.HourCounts[hour] = c
+		}
+	}
+
+	// 滚动窗口: 近 7/30 天活跃天数, 今天之前 7 天的日均消息
+	info.ActiveDays7 = activeDaysIn(cache.DailyActivity, today, 7)
+	info.ActiveDays30 = activeDaysIn(cache.DailyActivity, today, 30)
+	baseline := 0
+	for _, n := range info.DailyMessages[dailyWindow-1-avgWindow : dailyWindow-1] {
+		baseline += n
+	}
+	info.AvgMessages7 = float64(baseline) / avgWindow
+
 	return info
 }
 
+// activeDaysIn 统计截至今天的最近 days 天内有活动的天数
+func activeDaysIn(activity []model.DailyActivity, today time.Time, days int) int {
+	from := today.AddDate(0, 0, -(days - 1)).Format(dateLayout)
+	to := today.Format(dateLayout)
+	seen := make(map[string]struct{})
+	for _, d := range activity {
+		if d.Date >= from && d.Date <= to && (d.MessageCount > 0 || d.SessionCount > 0) {
+			seen[d.Date] = struct{}{}
+		}
+	}
+	return len(seen)
+}
+
+// dailySeries 返回截至今天的最近 days 天每日消息数, 无记录的日期为 0
+func dailySeries(activity []model.DailyActivity, today time.Time, days int) []int {
+	counts := make(map[string]int, len(activity))
+	for _, d := range activity {
+		counts[d.Date] += d.MessageCount
+	}
+	series := make([]int, days)
+	for i := range series {
+		series[i] = counts[today.AddDate(0, 0, i-days+1).Format(dateLayout)]
+	}
+	return series
+}
+
 // calcPeakHour 从小时计数中找出最活跃时段
 func calcPeakHour(hourCounts map[string]int, info *model.StatsInfo) {
 	if len(hourCounts) == 0 {
@@ -112,98 +198,25 @@ func calcPeakHour(hourCounts map[string]int, info *model.StatsInfo) {
 	}
 }
 
-// calcStreak 计算从今天/昨天开始的连续活跃天数
-func calcStreak(activity []model.DailyActivity, now time.Time) int {
-	if len(activity) == 0 {
-		return 0
-	}
-
-	// 收集并去重日期
-	dateSet := make(map[string]struct{}, len(activity))
-	for _, d := range activity {
-		dateSet[d.Date] = struct{}{}
-	}
-
-	dates := make([]string, 0, len(dateSet))
-	for d := range dateSet {
-		dates = append(dates, d)
-	}
-	sort.Sort(sort.Reverse(sort.StringSlice(dates)))
-
-	today := now.Format("2006-01-02")
-	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
-
-	// 连续天数必须从今天或昨天开始
-	if dates[0] != today && dates[0] != yesterday {
-		return 0
-	}
-
-	streak := 1
-	for i := 0; i < len(dates)-1; i++ {
-		curr, errC := time.Parse("2006-01-02", dates[i])
-		prev, errP := time.Parse("2006-01-02", dates[i+1])
-		if errC != nil || errP != nil {
-			break
-		}
-		// 用日期减一天比较, 避免浮点精度问题
-		if curr.AddDate(0, 0, -1).Equal(prev) {
-			streak++
-		} else {
-			break
-		}
-	}
+// calcStreak 计算截至今天的连续活跃天数, 今天尚无活动时从昨天算起
+func calcStreak(activity []model.DailyActivity, today time.Time) int {
+	streak, _ := streakStatus(activity, today)
 	return streak
 }
 
-// truncateToDate 将时间截断到日期 (去掉时分秒)
-func truncateToDate(t time.Time) time.Time {
-	y, m, d := t.Date()
-	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
-}
-
-// GetAchievement 根据统计数据返回成就徽章文本
-// Parameters:
-//   - info: 统计摘要
-//
-// Return:
-//   - string: 成就徽章文本, 无成就时返回空字符串
-func GetAchievement(info *model.StatsInfo) string {
-	if info == nil {
-		return ""
-	}
-
-	// 消息数成就 (优先级最高)
-	switch {
-	case info.TotalMessages >= 1000:
-		return "🏆 千言万语"
-	case info.TotalMessages >= 500:
-		return "🥇 消息达人"
-	case info.TotalMessages >= 100:
-		return "🥈 话唠新星"
-	}
-
-	// 会话数成就
-	switch {
-	case info.TotalSessions >= 100:
-		return "👑 会话之王"
-	case info.TotalSessions >= 50:
-		return "⭐ 会话专家"
-	}
-
-	// 连续活跃成就
-	switch {
-	case info.Streak >= 30:
-		return "🔥 月度坚持"
-	case info.Streak >= 7:
-		return "💪 周度坚持"
-	case info.Streak >= 3:
-		return "✊ 三连击"
+// streakStatus 按连续活跃规则计算当前连续天数与本月剩余冻结天数
+func streakStatus(activity []model.DailyActivity, today time.Time) (streak, freezesLeft int) {
+	messages := make(map[string]int, len(activity))
+	for _, d := range activity {
+		messages[d.Date] += d.MessageCount
 	}
-
-	// 活跃天数成就
-	if info.ActiveDays >= 30 {
-		return "🎖️ 老用户"
+	dates := make([]string, 0, len(messages))
+	for date, n := range messages {
+		if streakPolicy.counts(n) {
+			dates = append(dates, date)
+		}
 	}
 
-	return ""
+	w := walkStreaks(dates, today)
+	return w.current, max(0, streakPolicy.FreezesPerMonth-w.frozen[today.Format("2006-01")])
 }
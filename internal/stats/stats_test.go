@@ -11,7 +11,7 @@ import (
 var testNow = time.Date(2026, 2, 26, 10, 0, 0, 0, time.UTC)
 
 func TestCalcStreak_Empty(t *testing.T) {
-	got := calcStreak(nil, testNow)
+	got := calcStreak(nil, civilDate(testNow))
 	if got != 0 {
 		t.Errorf("calcStreak(nil) = %d, want 0", got)
 	}
@@ -21,7 +21,7 @@ func TestCalcStreak_OnlyToday(t *testing.T) {
 	activity := []model.DailyActivity{
 		{Date: "2026-02-26", MessageCount: 5},
 	}
-	got := calcStreak(activity, testNow)
+	got := calcStreak(activity, civilDate(testNow))
 	if got != 1 {
 		t.Errorf("calcStreak(today only) = %d, want 1", got)
 	}
@@ -31,7 +31,7 @@ func TestCalcStreak_OnlyYesterday(t *testing.T) {
 	activity := []model.DailyActivity{
 		{Date: "2026-02-25", MessageCount: 3},
 	}
-	got := calcStreak(activity, testNow)
+	got := calcStreak(activity, civilDate(testNow))
 	if got != 1 {
 		t.Errorf("calcStreak(yesterday only) = %d, want 1", got)
 	}
@@ -46,7 +46,7 @@ func TestCalcStreak_ConsecutiveDays(t *testing.T) {
 		{Date: "2026-02-25"},
 		{Date: "2026-02-26"},
 	}
-	got := calcStreak(activity, testNow)
+	got := calcStreak(activity, civilDate(testNow))
 	if got != 5 {
 		t.Errorf("calcStreak(5 consecutive) = %d, want 5", got)
 	}
@@ -59,7 +59,7 @@ func TestCalcStreak_BrokenStreak(t *testing.T) {
 		{Date: "2026-02-25"},
 		{Date: "2026-02-26"},
 	}
-	got := calcStreak(activity, testNow)
+	got := calcStreak(activity, civilDate(testNow))
 	if got != 2 {
 		t.Errorf("calcStreak(broken) = %d, want 2", got)
 	}
@@ -72,7 +72,7 @@ func TestCalcStreak_OldDatesOnly(t *testing.T) {
 		{Date: "2026-02-21"},
 		{Date: "2026-02-22"},
 	}
-	got := calcStreak(activity, testNow)
+	got := calcStreak(activity, civilDate(testNow))
 	if got != 0 {
 		t.Errorf("calcStreak(old dates) = %d, want 0", got)
 	}
@@ -86,7 +86,7 @@ func TestCalcStreak_DuplicateDates(t *testing.T) {
 		{Date: "2026-02-26"},
 		{Date: "2026-02-26"},
 	}
-	got := calcStreak(activity, testNow)
+	got := calcStreak(activity, civilDate(testNow))
 	if got != 2 {
 		t.Errorf("calcStreak(duplicates) = %d, want 2", got)
 	}
@@ -283,113 +283,49 @@ func TestComputeStatsInfo_CodingDays_MultiDay(t *testing.T) {
 	}
 }
 
-func TestGetAchievement_Nil(t *testing.T) {
-	got := GetAchievement(nil)
-	if got != "" {
-		t.Errorf("GetAchievement(nil) = %q, want empty", got)
+// TestComputeStatsInfo_Series 每日序列以今天结尾, 小时计数写入固定数组
+func TestComputeStatsInfo_Series(t *testing.T) {
+	cache := &model.StatsCache{
+		DailyActivity: []model.DailyActivity{
+			{Date: "2026-02-26", MessageCount: 5},
+			{Date: "2026-02-24", MessageCount: 3},
+			{Date: "2026-01-01", MessageCount: 9}, // 超出 30 天窗口
+		},
+		HourCounts: map[string]int{"9": 2, "23": 1, "24": 7, "x": 1},
 	}
-}
+	info := ComputeStatsInfo(cache, testNow)
 
-func TestGetAchievement_NoAchievement(t *testing.T) {
-	info := &model.StatsInfo{
-		TotalMessages: 10,
-		TotalSessions: 5,
-		Streak:        1,
-		ActiveDays:    3,
+	if len(info.DailyMessages) != dailyWindow {
+		t.Fatalf("len(DailyMessages) = %d, want %d", len(info.DailyMessages), dailyWindow)
 	}
-	got := GetAchievement(info)
-	if got != "" {
-		t.Errorf("GetAchievement = %q, want empty", got)
+	tail := info.DailyMessages[dailyWindow-3:]
+	if tail[0] != 3 || tail[1] != 0 || tail[2] != 5 {
+		t.Errorf("last 3 days = %v, want [3 0 5]", tail)
 	}
-}
-
-func TestGetAchievement_MessageTiers(t *testing.T) {
-	cases := []struct {
-		name     string
-		messages int
-		want     string
-	}{
-		{"below_100", 99, ""},
-		{"at_100", 100, "🥈 话唠新星"},
-		{"at_500", 500, "🥇 消息达人"},
-		{"at_1000", 1000, "🏆 千言万语"},
-		{"above_1000", 5000, "🏆 千言万语"},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			info := &model.StatsInfo{TotalMessages: tc.messages}
-			got := GetAchievement(info)
-			if got != tc.want {
-				t.Errorf("GetAchievement(messages=%d) = %q, want %q", tc.messages, got, tc.want)
-			}
-		})
+	if info.HourCounts[9] != 2 || info.HourCounts[23] != 1 {
+		t.Errorf("HourCounts = %v", info.HourCounts)
 	}
 }
 
-func TestGetAchievement_SessionTiers(t *testing.T) {
-	cases := []struct {
-		name     string
-		sessions int
-		want     string
-	}{
-		{"below_50", 49, ""},
-		{"at_50", 50, "⭐ 会话专家"},
-		{"at_100", 100, "👑 会话之王"},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			info := &model.StatsInfo{TotalSessions: tc.sessions}
-			got := GetAchievement(info)
-			if got != tc.want {
-				t.Errorf("GetAchievement(sessions=%d) = %q, want %q", tc.sessions, got, tc.want)
-			}
-		})
+func TestComputeStatsInfo_Windows(t *testing.T) {
+	cache := &model.StatsCache{
+		DailyActivity: []model.DailyActivity{
+			{Date: "2026-01-20", MessageCount: 99, SessionCount: 1}, // 30 天窗口之外
+			{Date: "2026-02-01", MessageCount: 10, SessionCount: 1},
+			{Date: "2026-02-19", MessageCount: 40, SessionCount: 2}, // 基线窗口首日
+			{Date: "2026-02-20", MessageCount: 0, SessionCount: 1},
+			{Date: "2026-02-24", MessageCount: 30, SessionCount: 1},
+			{Date: "2026-02-26", MessageCount: 45, SessionCount: 2},
+		},
 	}
-}
-
-func TestGetAchievement_StreakTiers(t *testing.T) {
-	cases := []struct {
-		name   string
-		streak int
-		want   string
-	}{
-		{"below_3", 2, ""},
-		{"at_3", 3, "✊ 三连击"},
-		{"at_7", 7, "💪 周度坚持"},
-		{"at_30", 30, "🔥 月度坚持"},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			info := &model.StatsInfo{Streak: tc.streak}
-			got := GetAchievement(info)
-			if got != tc.want {
-				t.Errorf("GetAchievement(streak=%d) = %q, want %q", tc.streak, got, tc.want)
-			}
-		})
+	info := ComputeStatsInfo(cache, testNow)
+	if info.ActiveDays7 != 3 {
+		t.Errorf("ActiveDays7 = %d, want 3", info.ActiveDays7)
 	}
-}
-
-func TestGetAchievement_ActiveDays(t *testing.T) {
-	info := &model.StatsInfo{ActiveDays: 30}
-	got := GetAchievement(info)
-	if got != "🎖️ 老用户" {
-		t.Errorf("GetAchievement(activeDays=30) = %q, want %q", got, "🎖️ 老用户")
+	if info.ActiveDays30 != 5 {
+		t.Errorf("ActiveDays30 = %d, want 5", info.ActiveDays30)
 	}
-}
-
-func TestGetAchievement_Priority(t *testing.T) {
-	// 消息成就优先级高于会话成就
-	info := &model.StatsInfo{
-		TotalMessages: 1000,
-		TotalSessions: 100,
-		Streak:        30,
-		ActiveDays:    30,
-	}
-	got := GetAchievement(info)
-	if got != "🏆 千言万语" {
-		t.Errorf("GetAchievement(all high) = %q, want %q", got, "🏆 千言万语")
+	if info.AvgMessages7 != 10 {
+		t.Errorf("AvgMessages7 = %.2f, want 10 (70 messages over 2-19..2-25)", info.AvgMessages7)
 	}
 }
@@ -0,0 +1,104 @@
+package stats
+
+import (
+	"sort"
+	"time"
+)
+
+// StreakPolicy 连续活跃规则
+type StreakPolicy struct {
+	WorkdaysOnly    bool // 只要求工作日活跃, 周末无活动不中断 (周末活跃仍计入)
+	FreezesPerMonth int  // 每月可冻结的天数, 冻结日无活动不中断 (也不计入天数)
+	MinMessages     int  // 单日消息数达到该值才算活跃, <= 0 时有记录即算
+}
+
+// streakPolicy 当前连续活跃规则, 由 SetStreakPolicy 在启动时设置
+var streakPolicy StreakPolicy
+
+// SetStreakPolicy 设置连续活跃规则
+// Parameters:
+//   - p: 连续活跃规则
+func SetStreakPolicy(p StreakPolicy) {
+	streakPolicy = p
+}
+
+// counts 按当前规则判断单日消息数是否算作活跃
+func (p StreakPolicy) counts(messages int) bool {
+	return p.MinMessages <= 0 || messages >= p.MinMessages
+}
+
+// streakWalk 按连续活跃规则遍历日期的结果
+type streakWalk struct {
+	runs    []Streak       // 各段连续区间, Days 只计活跃日
+	current int            // 截至 until 仍未中断的连续天数
+	frozen  map[string]int // 月份 (YYYY-MM) → 已用冻结天数
+}
+
+// walkStreaks 从最早的活跃日逐日遍历到 until, 按规则切分连续区间
+// until 当天无活动不中断 (今天还没结束); 冻结只在真正接上下一个活跃日
+// 或保住当前连续时才计为已用
+func walkStreaks(dates []string, until time.Time) streakWalk {
+	w := streakWalk{frozen: make(map[string]int)}
+	if len(dates) == 0 {
+		return w
+	}
+	sorted := append([]string(nil), dates...)
+	sort.Strings(sorted)
+	active := make(map[string]bool, len(sorted))
+	for _, d := range sorted {
+		active[d] = true
+	}
+	first, err := time.Parse(dateLayout, sorted[0])
+	if err != nil {
+		return w
+	}
+	end := until
+	if last, err := time.Parse(dateLayout, sorted[len(sorted)-1]); err == nil && last.After(end) {
+		end = last
+	}
+
+	open := false
+	var pending []string // 当前空档中已冻结的月份
+	for day := first; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		if active[date] {
+			if !open {
+				w.runs = append(w.runs, Streak{Start: date})
+				open = true
+			}
+			run := &w.runs[len(w.runs)-1]
+			run.Days++
+			run.End = date
+			pending = pending[:0]
+			continue
+		}
+		if !open || day.Equal(until) {
+			continue
+		}
+		if streakPolicy.WorkdaysOnly && isWeekend(day) {
+			continue
+		}
+		month := date[:7]
+		if w.frozen[month] < streakPolicy.FreezesPerMonth {
+			w.frozen[month]++
+			pending = append(pending, month)
+			continue
+		}
+		// 中断: 退还本次空档中白白使用的冻结
+		for _, m := range pending {
+			w.frozen[m]--
+		}
+		pending = pending[:0]
+		open = false
+	}
+	if open {
+		w.current = w.runs[len(w.runs)-1].Days
+	}
+	return w
+}
+
+// isWeekend 判断日期是否为周六或周日
+func isWeekend(day time.Time) bool {
+	wd := day.Weekday()
+	return wd == time.Saturday || wd == time.Sunday
+}
@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/nyan-statusline-cc/internal/model"
+)
+
+// withStreakPolicy 在测试期间设置连续活跃规则, 结束后恢复
+func withStreakPolicy(t *testing.T, p StreakPolicy) {
+	t.Helper()
+	SetStreakPolicy(p)
+	t.Cleanup(func() { SetStreakPolicy(StreakPolicy{}) })
+}
+
+// activityOn 每个日期一条记录, 消息数为 n
+func activityOn(n int, dates ...string) []model.DailyActivity {
+	activity := make([]model.DailyActivity, len(dates))
+	for i, d := range dates {
+		activity[i] = model.DailyActivity{Date: d, MessageCount: n, SessionCount: 1}
+	}
+	return activity
+}
+
+// TestStreak_WorkdaysOnly 周末 (2-21/22) 无活动不中断; testNow 为周四
+func TestStreak_WorkdaysOnly(t *testing.T) {
+	activity := activityOn(5, "2026-02-19", "2026-02-20", "2026-02-23", "2026-02-24", "2026-02-25", "2026-02-26")
+	if got := calcStreak(activity, civilDate(testNow)); got != 4 {
+		t.Errorf("default streak = %d, want 4", got)
+	}
+	withStreakPolicy(t, StreakPolicy{WorkdaysOnly: true})
+	if got := calcStreak(activity, civilDate(testNow)); got != 6 {
+		t.Errorf("workdays streak = %d, want 6", got)
+	}
+	// 工作日缺席仍会中断
+	if got := calcStreak(activityOn(5, "2026-02-19", "2026-02-23", "2026-02-24", "2026-02-25", "2026-02-26"), civilDate(testNow)); got != 4 {
+		t.Errorf("workdays streak with missing Friday = %d, want 4", got)
+	}
+}
+
+func TestStreak_Freezes(t *testing.T) {
+	activity := activityOn(5, "2026-02-20", "2026-02-21", "2026-02-23", "2026-02-24", "2026-02-26")
+	tests := []struct {
+		freezes    int
+		wantStreak int
+		wantLeft   int
+	}{
+		{0, 1, 0},
+		{1, 1, 0}, // 2-22 冻结后 2-25 无冻结可用
+		{2, 5, 0},
+		{3, 5, 1},
+	}
+	for _, tt := range tests {
+		withStreakPolicy(t, StreakPolicy{FreezesPerMonth: tt.freezes})
+		streak, left := streakStatus(activity, civilDate(testNow))
+		if streak != tt.wantStreak || left != tt.wantLeft {
+			t.Errorf("freezes=%d: streak=%d left=%d, want %d/%d", tt.freezes, streak, left, tt.wantStreak, tt.wantLeft)
+		}
+	}
+}
+
+// TestStreak_FreezesPerMonth 冻结额度按月计算
+func TestStreak_FreezesPerMonth(t *testing.T) {
+	withStreakPolicy(t, StreakPolicy{FreezesPerMonth: 1})
+	activity := activityOn(5, "2026-01-29", "2026-01-30", "2026-02-02", "2026-02-03")
+	now := testNow.AddDate(0, 0, -23) // 2026-02-03
+	streak, left := streakStatus(activity, civilDate(now))
+	if streak != 4 || left != 0 {
+		t.Errorf("streak=%d left=%d, want 4 (1-31 and 2-01 frozen) with 0 left", streak, left)
+	}
+}
+
+// TestStreak_FreezeRefund 没能接上的冻结不计入已用
+func TestStreak_FreezeRefund(t *testing.T) {
+	withStreakPolicy(t, StreakPolicy{FreezesPerMonth: 2})
+	streak, left := streakStatus(activityOn(5, "2026-02-10", "2026-02-26"), civilDate(testNow))
+	if streak != 1 || left != 2 {
+		t.Errorf("streak=%d left=%d, want 1 with 2 left", streak, left)
+	}
+	// 保住当前连续的冻结计为已用
+	streak, left = streakStatus(activityOn(5, "2026-02-23", "2026-02-24"), civilDate(testNow))
+	if streak != 2 || left != 1 {
+		t.Errorf("streak=%d left=%d, want 2 with 1 left (2-25 frozen)", streak, left)
+	}
+}
+
+func TestStreak_MinMessages(t *testing.T) {
+	withStreakPolicy(t, StreakPolicy{MinMessages: 5})
+	activity := append(activityOn(5, "2026-02-24", "2026-02-26"), activityOn(3, "2026-02-25")...)
+	if got := calcStreak(activity, civilDate(testNow)); got != 1 {
+		t.Errorf("streak = %d, want 1 (2-25 below minimum)", got)
+	}
+	// 同一天的多条记录合并后再判断
+	activity = append(activity, activityOn(2, "2026-02-25")...)
+	if got := calcStreak(activity, civilDate(testNow)); got != 3 {
+		t.Errorf("streak = %d, want 3 after merging 2-25", got)
+	}
+}
+
+// TestStreak_PolicyInRecordsAndReport 历史纪录与报告的最长连续使用同一规则
+func TestStreak_PolicyInRecordsAndReport(t *testing.T) {
+	withStreakPolicy(t, StreakPolicy{WorkdaysOnly: true})
+	activity := activityOn(5, "2026-02-12", "2026-02-13", "2026-02-16", "2026-02-17", "2026-02-24")
+	rec, _ := computeRecords(activity, civilDate(testNow))
+	if rec.LongestStreak != 4 || rec.LongestStreakStart != "2026-02-12" || rec.LongestStreakEnd != "2026-02-17" {
+		t.Errorf("LongestStreak = %d (%s ~ %s), want 4 (2026-02-12 ~ 2026-02-17)", rec.LongestStreak, rec.LongestStreakStart, rec.LongestStreakEnd)
+	}
+	r := BuildReport(&model.StatsCache{DailyActivity: activity}, testNow.AddDate(0, 0, -30), testNow, testNow)
+	if r.LongestStreak.Days != 4 {
+		t.Errorf("report LongestStreak = %+v, want 4 days", r.LongestStreak)
+	}
+}
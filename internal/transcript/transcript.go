@@ -0,0 +1,129 @@
+// Package transcript 读取 Claude Code 的会话记录 (projects/<项目>/<session>.jsonl)
+//
+// 每行是一条 JSON 记录, 只解析统计需要的字段: 类型、时间、会话、工作目录、模型和 token 用量.
+// 流式响应会把同一条 assistant 消息写入多行, 通过 DedupKey 去重.
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const projectsDirName = "projects"
+
+// Entry 会话记录中的一行
+type Entry struct {
+	Type      string    `json:"type"` // user/assistant/summary/...
+	Timestamp time.Time `json:"timestamp"`
+	SessionID string    `json:"sessionId"`
+	Cwd       string    `json:"cwd"`
+	RequestID string    `json:"requestId"`
+	Message   *Message  `json:"message"`
+}
+
+// Message 记录中的消息体
+type Message struct {
+	ID    string `json:"id"`
+	Model string `json:"model"`
+	Usage *Usage `json:"usage"`
+}
+
+// Usage assistant 消息的 token 用量
+type Usage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+}
+
+// Total 四类 token 之和
+func (u *Usage) Total() int64 {
+	if u == nil {
+		return 0
+	}
+	return u.InputTokens + u.OutputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+}
+
+// IsMessage 是否为用户或助手消息 (排除 summary 等元数据行)
+func (e *Entry) IsMessage() bool {
+	return e.Type == "user" || e.Type == "assistant"
+}
+
+// DedupKey 流式响应去重键, 无消息 ID 时返回空串 (不去重)
+func (e *Entry) DedupKey() string {
+	if e.Message == nil || e.Message.ID == "" {
+		return ""
+	}
+	return e.Message.ID + ":" + e.RequestID
+}
+
+// Files 列出 Claude 数据目录下的会话记录文件
+// Parameters:
+//   - claudeDir: Claude 数据目录 (包含 projects/)
+//   - since: 仅返回修改时间不早于该时间的文件, 零值表示全部
+//
+// Return:
+//   - []string: 文件路径
+func Files(claudeDir string, since time.Time) []string {
+	matches, _ := filepath.Glob(filepath.Join(claudeDir, projectsDirName, "*", "*.jsonl"))
+	if since.IsZero() {
+		return matches
+	}
+	files := matches[:0]
+	for _, path := range matches {
+		if fi, err := os.Stat(path); err == nil && !fi.ModTime().Before(since) {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// Read 逐行读取会话记录, 跳过无法解析的行
+// Parameters:
+//   - r: 输入源
+//   - fn: 每条记录的回调
+//
+// Return:
+//   - int64: 已完整读取的字节数 (不含末尾未写完的半行), 可用于增量读取
+//   - error: 读取错误
+func Read(r io.Reader, fn func(Entry)) (int64, error) {
+	br := bufio.NewReader(r)
+	var consumed int64
+	for {
+		line, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			// 末尾没有换行的半行可能仍在写入, 不计入偏移
+			return consumed, nil
+		}
+		if err != nil {
+			return consumed, err
+		}
+		consumed += int64(len(line))
+
+		var e Entry
+		if json.Unmarshal(line, &e) == nil && !e.Timestamp.IsZero() {
+			fn(e)
+		}
+	}
+}
+
+// ReadFile 读取整个会话记录文件
+// Parameters:
+//   - path: 文件路径
+//   - fn: 每条记录的回调
+//
+// Return:
+//   - error: 打开或读取错误
+func ReadFile(path string, fn func(Entry)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = Read(f, fn)
+	return err
+}
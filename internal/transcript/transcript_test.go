@@ -0,0 +1,76 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleLines = `{"type":"user","timestamp":"2026-02-26T10:00:00Z","sessionId":"s1","cwd":"/p","message":{"role":"user","content":"hi"}}
+{"type":"assistant","timestamp":"2026-02-26T10:00:05Z","sessionId":"s1","cwd":"/p","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4","usage":{"input_tokens":10,"output_tokens":20,"cache_creation_input_tokens":30,"cache_read_input_tokens":40}}}
+not json
+{"type":"summary","summary":"no timestamp"}
+`
+
+func TestRead(t *testing.T) {
+	var entries []Entry
+	n, err := Read(strings.NewReader(sampleLines+`{"type":"user","timestamp":"2026-`), func(e Entry) {
+		entries = append(entries, e)
+	})
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Read() got %d entries, want 2", len(entries))
+	}
+	if n != int64(len(sampleLines)) {
+		t.Errorf("consumed = %d, want %d (partial last line excluded)", n, len(sampleLines))
+	}
+
+	a := entries[1]
+	if !a.IsMessage() || a.Message.Model != "claude-sonnet-4" || a.Message.Usage.Total() != 100 {
+		t.Errorf("assistant entry parsed incorrectly: %+v", a)
+	}
+	if a.DedupKey() != "m1:r1" {
+		t.Errorf("DedupKey() = %q, want m1:r1", a.DedupKey())
+	}
+	if entries[0].DedupKey() != "" {
+		t.Errorf("user entry DedupKey() = %q, want empty", entries[0].DedupKey())
+	}
+}
+
+func TestUsageTotal_Nil(t *testing.T) {
+	var u *Usage
+	if u.Total() != 0 {
+		t.Error("nil Usage.Total() should be 0")
+	}
+}
+
+func TestFiles_Since(t *testing.T) {
+	dir := t.TempDir()
+	proj := filepath.Join(dir, projectsDirName, "-p")
+	if err := os.MkdirAll(proj, 0755); err != nil {
+		t.Fatal(err)
+	}
+	oldPath := filepath.Join(proj, "old.jsonl")
+	newPath := filepath.Join(proj, "new.jsonl")
+	for _, p := range []string{oldPath, newPath} {
+		if err := os.WriteFile(p, []byte(sampleLines), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(oldPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := Files(dir, time.Time{}); len(got) != 2 {
+		t.Errorf("Files(all) = %v, want 2 files", got)
+	}
+	got := Files(dir, time.Now().Add(-time.Hour))
+	if len(got) != 1 || got[0] != newPath {
+		t.Errorf("Files(since) = %v, want [%s]", got, newPath)
+	}
+}
@@ -0,0 +1,97 @@
+// Package usage 计算订阅计划的滚动用量窗口 (默认 5 小时)
+//
+// 窗口划分规则:
+//   - 第一条消息所在整点开始一个窗口, 持续 length
+//   - 消息超出当前窗口结束时间, 或距上一条消息超过 length, 则从该消息所在整点开始新窗口
+package usage
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/transcript"
+)
+
+// Block 一个用量窗口
+type Block struct {
+	Start  time.Time
+	End    time.Time
+	Tokens int64 // 窗口内 token 总量 (输入 + 输出 + 缓存写入 + 缓存读取)
+}
+
+// Remaining 距窗口重置的时长
+func (b *Block) Remaining(now time.Time) time.Duration {
+	return max(b.End.Sub(now), 0)
+}
+
+// stamp 一条去重后的用量记录
+type stamp struct {
+	at     time.Time
+	tokens int64
+}
+
+// CurrentBlock 从会话记录中计算当前活跃窗口
+// 读取进度与近期用量记录缓存在 dataDir, 每次只读取会话记录新增的内容
+// Parameters:
+//   - claudeDir: Claude 数据目录 (包含 projects/)
+//   - dataDir: 缓存文件所在目录
+//   - length: 窗口长度
+//   - now: 当前时间
+//
+// Return:
+//   - *Block: 当前窗口, 无活跃窗口时返回 nil
+func CurrentBlock(claudeDir, dataDir string, length time.Duration, now time.Time) *Block {
+	if length <= 0 {
+		return nil
+	}
+	// 只需回溯两个窗口长度即可确定当前窗口起点
+	since := now.Add(-2 * length)
+	c := loadCache(dataDir, length)
+	changed := c.prune(since)
+
+	recent := transcript.Files(claudeDir, since)
+	for path := range c.Files {
+		if !slices.Contains(recent, path) {
+			delete(c.Files, path)
+			changed = true
+		}
+	}
+	for _, path := range recent {
+		if c.scan(path, since) {
+			changed = true
+		}
+	}
+	if changed {
+		c.save(dataDir)
+	}
+
+	stamps := make([]stamp, 0, len(c.Records))
+	for _, r := range c.Records {
+		stamps = append(stamps, stamp{at: time.UnixMilli(r.At), tokens: r.Tokens})
+	}
+	return currentBlock(stamps, length, now)
+}
+
+// currentBlock 按窗口规则划分用量记录, 返回包含 now 的窗口
+func currentBlock(stamps []stamp, length time.Duration, now time.Time) *Block {
+	if length <= 0 || len(stamps) == 0 {
+		return nil
+	}
+	sort.Slice(stamps, func(i, j int) bool { return stamps[i].at.Before(stamps[j].at) })
+
+	var cur *Block
+	var last time.Time
+	for _, s := range stamps {
+		if cur == nil || !s.at.Before(cur.End) || s.at.Sub(last) >= length {
+			start := s.at.Truncate(time.Hour)
+			cur = &Block{Start: start, End: start.Add(length)}
+		}
+		cur.Tokens += s.tokens
+		last = s.at
+	}
+	if now.Before(cur.Start) || !now.Before(cur.End) {
+		return nil
+	}
+	return cur
+}
@@ -0,0 +1,112 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2026, 2, 26, 15, 30, 0, 0, time.UTC)
+
+func at(h, m int) time.Time {
+	return time.Date(2026, 2, 26, h, m, 0, 0, time.UTC)
+}
+
+func TestCurrentBlock_Empty(t *testing.T) {
+	if b := currentBlock(nil, 5*time.Hour, testNow); b != nil {
+		t.Errorf("currentBlock(nil) = %+v, want nil", b)
+	}
+}
+
+// TestCurrentBlock_StartsOnHour 窗口从第一条消息所在整点开始
+func TestCurrentBlock_StartsOnHour(t *testing.T) {
+	stamps := []stamp{{at(12, 40), 100}, {at(14, 10), 200}, {at(15, 20), 300}}
+	b := currentBlock(stamps, 5*time.Hour, testNow)
+	if b == nil {
+		t.Fatal("currentBlock() should return active block")
+	}
+	if !b.Start.Equal(at(12, 0)) || !b.End.Equal(at(17, 0)) {
+		t.Errorf("block = %v ~ %v, want 12:00 ~ 17:00", b.Start, b.End)
+	}
+	if b.Tokens != 600 {
+		t.Errorf("Tokens = %d, want 600", b.Tokens)
+	}
+	if got := b.Remaining(testNow); got != 90*time.Minute {
+		t.Errorf("Remaining() = %v, want 1h30m", got)
+	}
+}
+
+// TestCurrentBlock_RollsOver 超出窗口结束时间的消息开启新窗口
+func TestCurrentBlock_RollsOver(t *testing.T) {
+	stamps := []stamp{{at(8, 30), 100}, {at(12, 59), 100}, {at(13, 5), 50}}
+	b := currentBlock(stamps, 5*time.Hour, testNow)
+	if b == nil || !b.Start.Equal(at(13, 0)) || b.Tokens != 50 {
+		t.Errorf("block = %+v, want start 13:00 with 50 tokens", b)
+	}
+}
+
+// TestCurrentBlock_Expired 最后一个窗口已结束时无活跃窗口
+func TestCurrentBlock_Expired(t *testing.T) {
+	stamps := []stamp{{at(9, 0), 100}}
+	if b := currentBlock(stamps, 5*time.Hour, testNow); b != nil {
+		t.Errorf("currentBlock() = %+v, want nil for expired block", b)
+	}
+}
+
+// TestCurrentBlock_FromTranscripts 从会话记录读取并对流式重复行去重
+func TestCurrentBlock_FromTranscripts(t *testing.T) {
+	dir := t.TempDir()
+	proj := filepath.Join(dir, "projects", "-p")
+	if err := os.MkdirAll(proj, 0755); err != nil {
+		t.Fatal(err)
+	}
+	lines := `{"type":"assistant","timestamp":"2026-02-26T14:00:00Z","requestId":"r1","message":{"id":"m1","usage":{"input_tokens":10,"output_tokens":5}}}
+{"type":"assistant","timestamp":"2026-02-26T14:00:01Z","requestId":"r1","message":{"id":"m1","usage":{"input_tokens":10,"output_tokens":5}}}
+{"type":"assistant","timestamp":"2026-02-26T15:00:00Z","requestId":"r2","message":{"id":"m2","usage":{"input_tokens":1,"output_tokens":1}}}
+`
+	if err := os.WriteFile(filepath.Join(proj, "s1.jsonl"), []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dataDir := t.TempDir()
+	b := CurrentBlock(dir, dataDir, 5*time.Hour, testNow)
+	if b == nil || b.Tokens != 17 {
+		t.Errorf("CurrentBlock() = %+v, want 17 tokens", b)
+	}
+
+	// 追加内容只读取新增部分, 已缓存的记录不重复计入
+	f, err := os.OpenFile(filepath.Join(proj, "s1.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	more := `{"type":"assistant","timestamp":"2026-02-26T15:10:00Z","requestId":"r3","message":{"id":"m3","usage":{"output_tokens":3}}}
+`
+	_, _ = f.WriteString(more)
+	f.Close()
+	if b := CurrentBlock(dir, dataDir, 5*time.Hour, testNow); b == nil || b.Tokens != 20 {
+		t.Errorf("after append: CurrentBlock() = %+v, want 20 tokens", b)
+	}
+	if c := loadCache(dataDir, 5*time.Hour); len(c.Records) != 3 || c.Files[filepath.Join(proj, "s1.jsonl")] != int64(len(lines)+len(more)) {
+		t.Errorf("cache = %+v, want 3 records and offset at end of file", c)
+	}
+}
+
+// TestCurrentBlock_CachePrune 回溯范围之前的记录被清理, 窗口长度变化时重建缓存
+func TestCurrentBlock_CachePrune(t *testing.T) {
+	dataDir := t.TempDir()
+	c := &blockCache{Length: 5 * time.Hour, Records: []record{
+		{At: at(1, 0).UnixMilli(), Tokens: 100, Key: "old"},
+		{At: at(15, 0).UnixMilli(), Tokens: 7, Key: "new"},
+	}}
+	c.save(dataDir)
+
+	if b := CurrentBlock(t.TempDir(), dataDir, 5*time.Hour, testNow); b == nil || b.Tokens != 7 {
+		t.Errorf("CurrentBlock() = %+v, want 7 tokens from cached record", b)
+	}
+	if c := loadCache(dataDir, 5*time.Hour); len(c.Records) != 1 || c.Records[0].Key != "new" {
+		t.Errorf("records = %+v, want only the recent one", c.Records)
+	}
+	if c := loadCache(dataDir, 3*time.Hour); len(c.Records) != 0 {
+		t.Errorf("records = %+v, want empty cache for a new length", c.Records)
+	}
+}
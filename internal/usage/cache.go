@@ -0,0 +1,110 @@
+package usage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nyan-statusline-cc/internal/safefile"
+	"github.com/nyan-statusline-cc/internal/transcript"
+)
+
+const cacheFileName = "nyan-block-cache.json"
+
+// record 缓存的一条去重后的用量记录
+type record struct {
+	At     int64  `json:"at"` // Unix 毫秒
+	Tokens int64  `json:"tokens"`
+	Key    string `json:"key,omitempty"` // 去重键, 跨文件与跨增量读取去重
+}
+
+// blockCache 近期会话记录的读取进度与用量记录
+type blockCache struct {
+	Length  time.Duration    `json:"length"`  // 缓存对应的窗口长度, 变化时重建
+	Files   map[string]int64 `json:"files"`   // 文件路径 → 已读取的字节数
+	Records []record         `json:"records"` // 回溯范围内的用量记录
+
+	seen map[string]struct{}
+}
+
+// loadCache 读取缓存, 文件不存在、损坏或窗口长度变化时返回空缓存
+func loadCache(dir string, length time.Duration) *blockCache {
+	c := &blockCache{}
+	if raw, err := os.ReadFile(filepath.Join(dir, cacheFileName)); err == nil {
+		_ = json.Unmarshal(raw, c)
+	}
+	if c.Length != length {
+		c = &blockCache{Length: length}
+	}
+	if c.Files == nil {
+		c.Files = make(map[string]int64)
+	}
+	c.seen = make(map[string]struct{}, len(c.Records))
+	for _, r := range c.Records {
+		if r.Key != "" {
+			c.seen[r.Key] = struct{}{}
+		}
+	}
+	return c
+}
+
+// prune 清理回溯范围之前的用量记录, 返回是否有变化
+func (c *blockCache) prune(since time.Time) bool {
+	n := len(c.Records)
+	kept := c.Records[:0]
+	for _, r := range c.Records {
+		if r.At >= since.UnixMilli() {
+			kept = append(kept, r)
+		} else if r.Key != "" {
+			delete(c.seen, r.Key)
+		}
+	}
+	c.Records = kept
+	return len(kept) != n
+}
+
+// scan 从上次偏移处继续读取文件, 返回是否有进度
+// 文件变短 (被重写) 时从头读取, 已缓存的记录按去重键跳过
+func (c *blockCache) scan(path string, since time.Time) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	offset := c.Files[path]
+	if fi, err := f.Stat(); err != nil || fi.Size() == offset {
+		return false
+	} else if fi.Size() < offset {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, 0); err != nil {
+		return false
+	}
+	consumed, _ := transcript.Read(f, func(e transcript.Entry) {
+		if e.Message == nil || e.Message.Usage == nil || e.Timestamp.Before(since) {
+			return
+		}
+		key := e.DedupKey()
+		if key != "" {
+			if _, dup := c.seen[key]; dup {
+				return
+			}
+			c.seen[key] = struct{}{}
+		}
+		c.Records = append(c.Records, record{At: e.Timestamp.UnixMilli(), Tokens: e.Message.Usage.Total(), Key: key})
+	})
+	if consumed == 0 && offset == c.Files[path] {
+		return false
+	}
+	c.Files[path] = offset + consumed
+	return true
+}
+
+// save 写入缓存, 失败时忽略 (下次渲染重新读取)
+func (c *blockCache) save(dir string) {
+	if data, err := json.Marshal(c); err == nil {
+		_ = safefile.Write(filepath.Join(dir, cacheFileName), data)
+	}
+}
@@ -4,31 +4,76 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/nyan-statusline-cc/internal/config"
+	"github.com/nyan-statusline-cc/internal/notify"
 	"github.com/nyan-statusline-cc/internal/parser"
+	"github.com/nyan-statusline-cc/internal/paths"
 	"github.com/nyan-statusline-cc/internal/render"
 	"github.com/nyan-statusline-cc/internal/state"
 )
 
 func main() {
+	dataDir, args := extractDataDir(os.Args[1:])
+	p := paths.Resolve(paths.Options{DataDir: dataDir, BinaryDir: binaryDir()})
+
 	// config: 交互式配置
-	if len(os.Args) == 2 && os.Args[1] == "config" {
-		binaryDir := filepath.Dir(os.Args[0])
-		if err := config.RunInteractive(binaryDir); err != nil {
+	if len(args) == 1 && args[0] == "config" {
+		if err := config.RunInteractive(p.DataDir); err != nil {
 			fmt.Fprintf(os.Stderr, "config error: %v\n", err)
 			os.Exit(1)
 		}
 		return
 	}
 
+	// stats: 完整统计报告
+	if len(args) >= 1 && args[0] == "stats" {
+		if err := runStats(args[1:], p, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "stats error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// achievements: 徽章列表
+	if len(args) >= 1 && args[0] == "achievements" {
+		if err := runAchievements(args[1:], p, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "achievements error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// projects: 项目排行
+	if len(args) >= 1 && args[0] == "projects" {
+		if err := runProjects(args[1:], p, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "projects error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// models: 模型用量
+	if len(args) >= 1 && args[0] == "models" {
+		if err := runModels(args[1:], p, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "models error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// --state processing/completed: hooks 调用模式, 写入状态后退出
-	if len(os.Args) == 3 && os.Args[1] == "--state" {
-		binaryDir := filepath.Dir(os.Args[0])
-		if err := state.SetStatus(binaryDir, os.Args[2]); err != nil {
+	if len(args) == 2 && args[0] == "--state" {
+		if err := state.SetStatus(p.DataDir, args[1]); err != nil {
 			fmt.Fprintf(os.Stderr, "set state error: %v\n", err)
 			os.Exit(1)
 		}
+		// processing → completed: 处理时长达到阈值时发送终端通知
+		if s := state.Load(p.DataDir); s != nil && s.Status == state.StatusCompleted && s.TurnDuration > 0 {
+			cfg := config.Load(p.DataDir)
+			_ = notify.TurnCompleted(cfg.Notify, s.TurnDuration)
+		}
 		return
 	}
 
@@ -38,5 +83,35 @@ func main() {
 		fmt.Fprintf(os.Stderr, "parse error: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Print(render.Render(data))
+	fmt.Print(render.Render(data, p))
+}
+
+// extractDataDir 从参数中取出 --data-dir <dir> / --data-dir=<dir>, 返回目录和剩余参数
+func extractDataDir(args []string) (string, []string) {
+	var dir string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--data-dir" && i+1 < len(args):
+			dir = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--data-dir="):
+			dir = strings.TrimPrefix(args[i], "--data-dir=")
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return dir, rest
+}
+
+// binaryDir 返回二进制所在目录 (解析符号链接), 用于兼容旧版布局
+func binaryDir() string {
+	execPath, err := os.Executable()
+	if err != nil {
+		return filepath.Dir(os.Args[0])
+	}
+	if resolved, err := filepath.EvalSymlinks(execPath); err == nil {
+		execPath = resolved
+	}
+	return filepath.Dir(execPath)
 }